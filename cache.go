@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"sync/atomic"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/lru"
+	"github.com/sevlyar/graphql-go/internal/query"
+	"github.com/sevlyar/graphql-go/internal/validation"
+)
+
+// QueryCache enables a cache for parsed and validated query documents, keyed by the query string.
+// At most size documents are kept, the least recently used one is evicted first. Documents which
+// failed to parse or validate are cached together with their errors.
+func QueryCache(size int) SchemaOpt {
+	return func(s *Schema) {
+		s.queryCache = &queryCache{entries: lru.New(size)}
+	}
+}
+
+// QueryCacheStats are the counters of the query cache.
+type QueryCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Len    int
+}
+
+// QueryCacheStats returns the counters of the query cache. They are all zero if the schema was
+// created without the QueryCache option.
+func (s *Schema) QueryCacheStats() QueryCacheStats {
+	if s.queryCache == nil {
+		return QueryCacheStats{}
+	}
+	return s.queryCache.stats()
+}
+
+// parseAndValidate returns the validated document for the query string, using the cache if
+// enabled. Only trusted documents are accepted if the schema has them.
+func (s *Schema) parseAndValidate(queryString string) (*query.Document, []*errors.QueryError) {
+	if s.trusted != nil {
+		return s.trustedDocument(queryString) // parsed and validated by ParseSchema
+	}
+
+	if s.queryCache != nil {
+		if e, ok := s.queryCache.get(queryString); ok {
+			return e.doc, e.errs
+		}
+	}
+
+	doc, errs := s.parseAndValidateUncached(queryString)
+	if s.queryCache != nil {
+		s.queryCache.add(queryString, &cacheEntry{doc: doc, errs: errs})
+	}
+	return doc, errs
+}
+
+func (s *Schema) parseAndValidateUncached(queryString string) (*query.Document, []*errors.QueryError) {
+	doc, qErr := query.Parse(queryString)
+	if qErr != nil {
+		return nil, []*errors.QueryError{qErr}
+	}
+
+	if errs := validation.Validate(s.schema, doc, validation.Options{MaxDepth: s.maxDepth}); len(errs) != 0 {
+		return nil, errs
+	}
+	return doc, nil
+}
+
+type queryCache struct {
+	hits    uint64 // accessed atomically, first for 64-bit alignment
+	misses  uint64 // accessed atomically
+	entries *lru.Cache
+}
+
+type cacheEntry struct {
+	doc  *query.Document
+	errs []*errors.QueryError
+}
+
+func (c *queryCache) get(queryString string) (*cacheEntry, bool) {
+	e, ok := c.entries.Get(queryString)
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	return e.(*cacheEntry), true
+}
+
+func (c *queryCache) add(queryString string, e *cacheEntry) {
+	c.entries.Add(queryString, e)
+}
+
+func (c *queryCache) stats() QueryCacheStats {
+	return QueryCacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Len:    c.entries.Len(),
+	}
+}
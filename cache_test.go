@@ -0,0 +1,56 @@
+package graphql_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+)
+
+func TestQueryCache(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.QueryCache(2))
+
+	queries := []string{
+		`{ hero { name } }`,
+		`{ hero { name } }`,
+		`{ hero { id } }`,
+		`{ unknown }`,
+		`{ hero { name } }`,
+		`{ unknown }`,
+	}
+	for _, q := range queries {
+		s.Exec(context.Background(), q, "", nil)
+	}
+
+	want := graphql.QueryCacheStats{Hits: 2, Misses: 4, Len: 2}
+	if got := s.QueryCacheStats(); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+
+	result := s.Exec(context.Background(), `{ unknown }`, "", nil)
+	if len(result.Errors) != 1 {
+		t.Errorf("expected cached validation error, got %v", result.Errors)
+	}
+}
+
+func TestQueryCacheConcurrent(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.QueryCache(10))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := s.Exec(context.Background(), `query($episode: Episode) { hero(episode: $episode) { name friends { name } } }`, "", map[string]interface{}{"episode": "EMPIRE"})
+			if len(result.Errors) != 0 {
+				t.Error(result.Errors[0])
+			}
+			if string(result.Data) != `{"hero":{"name":"Luke Skywalker","friends":[{"name":"Han Solo"},{"name":"Leia Organa"},{"name":"C-3PO"},{"name":"R2-D2"}]}}` {
+				t.Errorf("unexpected result %s", result.Data)
+			}
+		}()
+	}
+	wg.Wait()
+}
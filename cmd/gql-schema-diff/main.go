@@ -0,0 +1,48 @@
+// Command gql-schema-diff compares two versions of a schema and prints the changes between them.
+// It exits with status 1 if any change is breaking, so it can guard a release pipeline:
+//
+//	gql-schema-diff -old previous.graphql -new schema.graphql
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/sevlyar/graphql-go/schemadiff"
+)
+
+var (
+	oldPath   = flag.String("old", "", "path to the previous graphql schema")
+	newPath   = flag.String("new", "", "path to the new graphql schema")
+	dangerous = flag.Bool("fail-dangerous", false, "also exit with status 1 on dangerous changes")
+)
+
+func main() {
+	flag.Parse()
+
+	oldRaw, err := ioutil.ReadFile(*oldPath)
+	if err != nil {
+		log.Fatalf(`can't open schema by path '%s' - err: %s`, *oldPath, err.Error())
+	}
+	newRaw, err := ioutil.ReadFile(*newPath)
+	if err != nil {
+		log.Fatalf(`can't open schema by path '%s' - err: %s`, *newPath, err.Error())
+	}
+
+	changes, err := schemadiff.Compare(string(oldRaw), string(newRaw))
+	if err != nil {
+		log.Fatalf(`comparison failed: %s`, err)
+	}
+
+	fail := false
+	for _, c := range changes {
+		fmt.Println(c)
+		fail = fail || c.Criticality == schemadiff.Breaking || (*dangerous && c.Criticality == schemadiff.Dangerous)
+	}
+	if fail {
+		os.Exit(1)
+	}
+}
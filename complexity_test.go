@@ -0,0 +1,104 @@
+package graphql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+)
+
+const costSchema = `
+	directive @cost(complexity: Int = 1, multipliers: [String!]) on FIELD_DEFINITION
+
+	schema {
+		query: Query
+	}
+
+	type Query {
+		items(first: Int = 10): [Item!]!
+		search(text: String!, max: Int!): [Item!]! @cost(complexity: 5, multipliers: ["max"])
+	}
+
+	type Item {
+		name: String!
+		related(limit: Int!): [Item!]!
+		expensive: String! @cost(complexity: 20)
+	}
+`
+
+type costResolver struct{}
+
+func (r *costResolver) Items(args struct{ First int32 }) []*costItem {
+	return []*costItem{{}}
+}
+
+func (r *costResolver) Search(args struct {
+	Text string
+	Max  int32
+}) []*costItem {
+	return nil
+}
+
+type costItem struct{}
+
+func (r *costItem) Name() string {
+	return "item"
+}
+
+func (r *costItem) Related(args struct{ Limit int32 }) []*costItem {
+	return nil
+}
+
+func (r *costItem) Expensive() string {
+	return "expensive"
+}
+
+func TestComplexity(t *testing.T) {
+	s := graphql.MustParseSchema(costSchema, &costResolver{}, graphql.MaxComplexity(500))
+
+	tests := []struct {
+		query      string
+		variables  map[string]interface{}
+		complexity int
+		rejected   bool
+	}{
+		{
+			query:      `{ items { name } }`,
+			complexity: 20, // (1 + 1) * 10 by default
+		},
+		{
+			query:      `{ items(first: 2) { name expensive } }`,
+			complexity: 44, // (1 + 1 + 20) * 2
+		},
+		{
+			query:      `query($max: Int!) { search(text: "a", max: $max) { ...itemName } } fragment itemName on Item { name }`,
+			variables:  map[string]interface{}{"max": 3.0},
+			complexity: 18, // (5 + 1) * 3
+		},
+		{
+			query:      `{ items(first: 5) { related(limit: 10) { name } } }`,
+			complexity: 105, // (1 + (1 + 1) * 10) * 5
+		},
+		{
+			query:      `{ items(first: 100) { related(limit: 100) { name } } }`,
+			complexity: 20100,
+			rejected:   true,
+		},
+	}
+
+	for _, test := range tests {
+		result := s.Exec(context.Background(), test.query, "", test.variables)
+		if got := result.Extensions["complexity"]; got != test.complexity {
+			t.Errorf("%s: got complexity %v, want %d", test.query, got, test.complexity)
+		}
+		if test.rejected {
+			if len(result.Errors) != 1 || result.Data != nil {
+				t.Errorf("%s: expected query to be rejected, got %s %v", test.query, result.Data, result.Errors)
+			}
+			continue
+		}
+		if len(result.Errors) != 0 {
+			t.Errorf("%s: unexpected error %s", test.query, result.Errors[0])
+		}
+	}
+}
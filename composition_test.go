@@ -0,0 +1,147 @@
+package graphql_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+type compositionResolver struct{}
+
+func (r *compositionResolver) Me() *compositionUserResolver {
+	return &compositionUserResolver{}
+}
+
+func (r *compositionResolver) Orders() []*compositionOrderResolver {
+	return []*compositionOrderResolver{{}}
+}
+
+type compositionUserResolver struct{}
+
+func (r *compositionUserResolver) Name() string { return "Alice" }
+
+func (r *compositionUserResolver) Orders() []*compositionOrderResolver {
+	return []*compositionOrderResolver{{}}
+}
+
+type compositionOrderResolver struct{}
+
+func (r *compositionOrderResolver) Total() int32 { return 42 }
+
+func TestParseSchemaFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schema.graphql": {Data: []byte(`
+			schema {
+				query: Query
+			}
+
+			type Query {
+				me: User!
+			}
+		`)},
+		"users/user.graphql": {Data: []byte(`
+			type User {
+				name: String!
+			}
+		`)},
+		"orders/order.graphql": {Data: []byte(`
+			type Order {
+				total: Int!
+			}
+
+			extend type User {
+				orders: [Order!]!
+			}
+
+			extend type Query {
+				orders: [Order!]!
+			}
+		`)},
+		"README.md": {Data: []byte(`not a schema`)},
+	}
+
+	s, err := graphql.ParseSchemaFiles(fsys, []string{"schema.graphql", "*/*.graphql"}, &compositionResolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gqltesting.RunTest(t, &gqltesting.Test{
+		Schema: s,
+		Query: `
+			{
+				me {
+					name
+					orders {
+						total
+					}
+				}
+				orders {
+					total
+				}
+			}
+		`,
+		ExpectedResult: `
+			{
+				"me": {
+					"name": "Alice",
+					"orders": [{"total": 42}]
+				},
+				"orders": [{"total": 42}]
+			}
+		`,
+	})
+}
+
+func TestParseSchemaFilesConflicts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.graphql": {Data: []byte(`
+			schema {
+				query: Query
+			}
+
+			type Query {
+				count: Int
+			}
+
+			scalar Time
+		`)},
+		"b.graphql": {Data: []byte(`
+			extend type Query {
+				count: String
+			}
+
+			scalar Time
+
+			schema {
+				query: Other
+			}
+		`)},
+	}
+
+	_, err := graphql.ParseSchemaFiles(fsys, []string{"*.graphql"}, nil)
+	errs, ok := err.(errors.SchemaErrors)
+	if !ok {
+		t.Fatalf("got error %#v, want errors.SchemaErrors", err)
+	}
+	want := "" +
+		`graphql: field "count" of type "Query" is already defined with the type "Int", conflicting with "String" (b.graphql: line 3, column 5) (a.graphql: line 7, column 5)` + "\n" +
+		`graphql: type "Time" is defined more than once (b.graphql: line 6, column 11) (a.graphql: line 10, column 11)` + "\n" +
+		`graphql: schema is defined more than once (b.graphql: line 8, column 4) (a.graphql: line 2, column 4)` + "\n" +
+		`graphql: query operation is already defined with the type "Query", conflicting with "Other" (b.graphql: line 9, column 12) (a.graphql: line 3, column 12)`
+	if errs.Error() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", errs, want)
+	}
+
+	_, err = graphql.ParseSchemaFiles(fsys, []string{"*.gql"}, nil)
+	if err == nil || err.Error() != `graphql: pattern "*.gql" matches no schema files` {
+		t.Errorf("got error %v", err)
+	}
+
+	fsys["b.graphql"] = &fstest.MapFile{Data: []byte(`type Broken {`)}
+	_, err = graphql.ParseSchemaFiles(fsys, []string{"*.graphql"}, nil)
+	if qErr, ok := err.(*errors.QueryError); !ok || qErr.Locations[0].Source != "b.graphql" {
+		t.Errorf("got error %v, want a syntax error in b.graphql", err)
+	}
+}
@@ -0,0 +1,262 @@
+// Package dataloader coalesces the keys loaded by resolvers into batches.
+//
+// A Loader is created once, usually as a global variable. When used during the execution of a
+// GraphQL request, all keys that are requested while the request makes progress are collected
+// and the batch function is called as soon as every resolver of the request is either done or
+// waiting for a Load. Results are cached for the lifetime of the request. While waiting, a
+// resolver gives up its slot of graphql.MaxParallelism, so all siblings of a list are able to
+// register their keys.
+//
+// Load has to be called with the context passed to the resolver and from the resolver's
+// goroutine. Outside of a GraphQL request, keys are only batched within the time window given by
+// the Wait option and results are not cached.
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sevlyar/graphql-go/internal/exec/batch"
+)
+
+// BatchFunc loads the values of the given keys. It has to return exactly one Result per key in the
+// same order as the keys.
+type BatchFunc func(ctx context.Context, keys []interface{}) []*Result
+
+// Result is the value or error loaded for a single key.
+type Result struct {
+	Value interface{}
+	Error error
+}
+
+// Loader loads values in batches. It is safe for concurrent use.
+type Loader struct {
+	batchFn  BatchFunc
+	maxBatch int
+	wait     time.Duration
+	noCache  bool
+
+	detachedOnce sync.Once
+	detached     *requestLoader
+}
+
+// Option configures a Loader.
+type Option func(*Loader)
+
+// MaxBatch limits the number of keys passed to a single call of the batch function. The default is
+// no limit.
+func MaxBatch(n int) Option {
+	return func(l *Loader) {
+		l.maxBatch = n
+	}
+}
+
+// Wait is the maximum time a batch collects keys before it gets dispatched, even if the request
+// could still register more keys. The default is 16 milliseconds. Zero disables the timer for
+// batches of a GraphQL request.
+func Wait(d time.Duration) Option {
+	return func(l *Loader) {
+		l.wait = d
+	}
+}
+
+// NoCache disables the per request cache, so every Load passes its key to the batch function.
+func NoCache() Option {
+	return func(l *Loader) {
+		l.noCache = true
+	}
+}
+
+// New returns a loader which loads values with the given batch function.
+func New(batchFn BatchFunc, opts ...Option) *Loader {
+	l := &Loader{
+		batchFn: batchFn,
+		wait:    16 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Load returns the value for the given key. Keys have to be comparable.
+func (l *Loader) Load(ctx context.Context, key interface{}) (interface{}, error) {
+	rl := l.forRequest(ctx)
+	return rl.enqueue(ctx, key).wait(ctx)
+}
+
+// LoadMany returns the values for the given keys. All keys end up in the same batch unless the
+// batch size is limited.
+func (l *Loader) LoadMany(ctx context.Context, keys []interface{}) []*Result {
+	rl := l.forRequest(ctx)
+	pending := make([]*pendingResult, len(keys))
+	for i, key := range keys {
+		pending[i] = rl.enqueue(ctx, key)
+	}
+
+	results := make([]*Result, len(keys))
+	for i, p := range pending {
+		v, err := p.wait(ctx)
+		results[i] = &Result{Value: v, Error: err}
+	}
+	return results
+}
+
+// Prime stores the value for the key in the cache of the request, unless it is already cached.
+func (l *Loader) Prime(ctx context.Context, key interface{}, value interface{}) {
+	rl := l.forRequest(ctx)
+	if rl.cache == nil {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if _, ok := rl.cache[key]; !ok {
+		rl.cache[key] = &pendingResult{result: &Result{Value: value}}
+	}
+}
+
+// Clear removes the key from the cache of the request.
+func (l *Loader) Clear(ctx context.Context, key interface{}) {
+	rl := l.forRequest(ctx)
+	if rl.cache == nil {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.cache, key)
+}
+
+func (l *Loader) forRequest(ctx context.Context) *requestLoader {
+	if s := batch.FromContext(ctx); s != nil {
+		return s.State(l, func() interface{} {
+			rl := &requestLoader{loader: l, sched: s, ctx: s.Context()}
+			if !l.noCache {
+				rl.cache = make(map[interface{}]*pendingResult)
+			}
+			return rl
+		}).(*requestLoader)
+	}
+
+	l.detachedOnce.Do(func() {
+		l.detached = &requestLoader{loader: l, sched: batch.NewDetachedScheduler()}
+	})
+	return l.detached
+}
+
+// requestLoader holds the state of a loader for a single request.
+type requestLoader struct {
+	loader *Loader
+	sched  *batch.Scheduler
+	ctx    context.Context // nil if detached
+
+	mu    sync.Mutex
+	cache map[interface{}]*pendingResult
+	cur   *pendingBatch
+}
+
+type pendingBatch struct {
+	b       *batch.Batch
+	keys    []interface{}
+	results []*Result
+}
+
+type pendingResult struct {
+	rl     *requestLoader
+	pb     *pendingBatch
+	index  int
+	result *Result // set if the value was primed
+}
+
+func (rl *requestLoader) enqueue(ctx context.Context, key interface{}) *pendingResult {
+	rl.mu.Lock()
+	if p, ok := rl.cache[key]; ok {
+		rl.mu.Unlock()
+		return p
+	}
+
+	pb := rl.cur
+	if pb == nil {
+		pb = rl.newBatch(ctx)
+		rl.cur = pb
+	}
+	p := &pendingResult{rl: rl, pb: pb, index: len(pb.keys)}
+	pb.keys = append(pb.keys, key)
+	if rl.cache != nil {
+		rl.cache[key] = p
+	}
+
+	full := rl.loader.maxBatch > 0 && len(pb.keys) >= rl.loader.maxBatch
+	if full {
+		rl.cur = nil
+	}
+	rl.mu.Unlock()
+
+	if full {
+		rl.sched.Fire(pb.b)
+	}
+	return p
+}
+
+func (rl *requestLoader) newBatch(ctx context.Context) *pendingBatch {
+	batchCtx := rl.ctx
+	if batchCtx == nil {
+		batchCtx = ctx // detached batches use the context of their first key
+	}
+
+	pb := &pendingBatch{}
+	pb.b = rl.sched.NewBatch(func() {
+		rl.mu.Lock()
+		if rl.cur == pb {
+			rl.cur = nil // no more keys for this batch
+		}
+		keys := pb.keys
+		rl.mu.Unlock()
+
+		pb.results = rl.loader.call(batchCtx, keys)
+	})
+
+	if rl.loader.wait > 0 || rl.ctx == nil {
+		time.AfterFunc(rl.loader.wait, func() {
+			rl.sched.Fire(pb.b)
+		})
+	}
+	return pb
+}
+
+func (l *Loader) call(ctx context.Context, keys []interface{}) (results []*Result) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			results = errorResults(len(keys), fmt.Errorf("dataloader: panic occurred: %v", panicValue))
+		}
+	}()
+
+	results = l.batchFn(ctx, keys)
+	if len(results) != len(keys) {
+		return errorResults(len(keys), fmt.Errorf("dataloader: batch function returned %d results for %d keys", len(results), len(keys)))
+	}
+	return results
+}
+
+func errorResults(n int, err error) []*Result {
+	results := make([]*Result, n)
+	for i := range results {
+		results[i] = &Result{Error: err}
+	}
+	return results
+}
+
+func (p *pendingResult) wait(ctx context.Context) (interface{}, error) {
+	if p.result != nil {
+		return p.result.Value, p.result.Error
+	}
+	if err := p.rl.sched.Wait(ctx, p.pb.b); err != nil {
+		return nil, err
+	}
+	r := p.pb.results[p.index]
+	if r == nil {
+		return nil, nil
+	}
+	return r.Value, r.Error
+}
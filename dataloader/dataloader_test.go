@@ -0,0 +1,156 @@
+package dataloader_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/dataloader"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const userSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		users(ids: [ID!]!): [User!]!
+	}
+
+	type User {
+		id: ID!
+		name: String!
+		friend: User!
+	}
+`
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (rec *batchRecorder) loader(opts ...dataloader.Option) *dataloader.Loader {
+	return dataloader.New(func(ctx context.Context, keys []interface{}) []*dataloader.Result {
+		ids := make([]string, len(keys))
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			ids[i] = string(key.(graphql.ID))
+			results[i] = &dataloader.Result{Value: "user " + ids[i]}
+		}
+		sort.Strings(ids)
+		rec.mu.Lock()
+		rec.batches = append(rec.batches, ids)
+		rec.mu.Unlock()
+		return results
+	}, opts...)
+}
+
+type userQueryResolver struct {
+	names *dataloader.Loader
+}
+
+func (r *userQueryResolver) Users(args struct{ IDs []graphql.ID }) []*userResolver {
+	l := make([]*userResolver, len(args.IDs))
+	for i, id := range args.IDs {
+		l[i] = &userResolver{id: id, names: r.names}
+	}
+	return l
+}
+
+type userResolver struct {
+	id    graphql.ID
+	names *dataloader.Loader
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return r.id
+}
+
+func (r *userResolver) Name(ctx context.Context) (string, error) {
+	v, err := r.names.Load(ctx, r.id)
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+func (r *userResolver) Friend() *userResolver {
+	return &userResolver{id: r.id + "0", names: r.names}
+}
+
+func TestBatching(t *testing.T) {
+	rec := &batchRecorder{}
+	s := graphql.MustParseSchema(userSchema, &userQueryResolver{names: rec.loader(dataloader.Wait(0))}, graphql.MaxParallelism(2))
+
+	gqltesting.RunTest(t, &gqltesting.Test{
+		Schema: s,
+		Query: `
+			{
+				users(ids: ["1", "2", "3", "1"]) {
+					name
+					friend {
+						name
+					}
+				}
+			}
+		`,
+		ExpectedResult: `
+			{
+				"users": [
+					{"name": "user 1", "friend": {"name": "user 10"}},
+					{"name": "user 2", "friend": {"name": "user 20"}},
+					{"name": "user 3", "friend": {"name": "user 30"}},
+					{"name": "user 1", "friend": {"name": "user 10"}}
+				]
+			}
+		`,
+	})
+
+	want := [][]string{{"1", "10", "2", "20", "3", "30"}}
+	if fmt.Sprint(rec.batches) != fmt.Sprint(want) {
+		t.Errorf("got batches %v, want %v", rec.batches, want)
+	}
+}
+
+func TestMaxBatch(t *testing.T) {
+	rec := &batchRecorder{}
+	s := graphql.MustParseSchema(userSchema, &userQueryResolver{names: rec.loader(dataloader.Wait(0), dataloader.MaxBatch(2))})
+
+	result := s.Exec(context.Background(), `{ users(ids: ["1", "2", "3"]) { name } }`, "", nil)
+	if len(result.Errors) != 0 {
+		t.Fatal(result.Errors[0])
+	}
+
+	n := 0
+	for _, b := range rec.batches {
+		if len(b) > 2 {
+			t.Errorf("batch %v exceeds the maximum size", b)
+		}
+		n += len(b)
+	}
+	if n != 3 {
+		t.Errorf("got %d keys in %d batches, want 3", n, len(rec.batches))
+	}
+}
+
+func TestLoadOutsideOfRequest(t *testing.T) {
+	rec := &batchRecorder{}
+	l := rec.loader()
+
+	results := l.LoadMany(context.Background(), []interface{}{graphql.ID("a"), graphql.ID("b")})
+	for _, r := range results {
+		if r.Error != nil {
+			t.Fatal(r.Error)
+		}
+	}
+	if results[0].Value != "user a" || results[1].Value != "user b" {
+		t.Errorf("unexpected results %v, %v", results[0].Value, results[1].Value)
+	}
+	if len(rec.batches) != 1 {
+		t.Errorf("got %d batches, want 1", len(rec.batches))
+	}
+}
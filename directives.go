@@ -0,0 +1,200 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/exec/packer"
+	"github.com/sevlyar/graphql-go/internal/schema"
+)
+
+// Directive registers the Go implementation of a custom directive used on field definitions of
+// the schema. The directive has to be declared in the schema with the FIELD_DEFINITION location.
+// Every resolver of a field carrying the directive is wrapped by the implementation, which is a
+// function of the form
+//
+//	func(ctx context.Context, args *struct{ ... }, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error)
+//
+// The arguments of the directive are packed into args like the arguments of resolvers. The args
+// parameter is omitted if the directive has no arguments. The implementation behaves like a
+// FieldMiddleware: it may call next, alter its result or short-circuit with a value or error.
+// Several directives on the same field are applied in the order they are written, the first one
+// being the outermost. Middlewares registered with the Middleware option wrap all directives.
+//
+// Directives on interface fields also apply to the fields implementing them, unless the
+// implementing field has implemented directives of its own. Those replace the directives of the
+// interface when the field is selected through the implementing type.
+func Directive(name string, implementation interface{}) SchemaOpt {
+	return func(s *Schema) {
+		if s.directiveImpls == nil {
+			s.directiveImpls = make(map[string]interface{})
+		}
+		s.directiveImpls[name] = implementation
+	}
+}
+
+type fieldKey struct {
+	typeName  string
+	fieldName string
+}
+
+var (
+	contextType         = reflect.TypeOf((*context.Context)(nil)).Elem()
+	fieldInfoType       = reflect.TypeOf(&FieldInfo{})
+	fieldResolverType   = reflect.TypeOf(FieldResolver(nil))
+	emptyInterfaceType  = reflect.TypeOf((*interface{})(nil)).Elem()
+	errorInterfaceType  = reflect.TypeOf((*error)(nil)).Elem()
+	directiveImplFormat = "func(context.Context, [args,] *graphql.FieldInfo, graphql.FieldResolver) (interface{}, error)"
+)
+
+// applyDirectives checks the directive implementations against their declarations and prepares
+// them for all fields using them.
+func (s *Schema) applyDirectives() error {
+	if len(s.directiveImpls) == 0 {
+		return nil
+	}
+
+	argTypes := make(map[string]reflect.Type)
+
+	for name, impl := range s.directiveImpls {
+		decl, ok := s.schema.Directives[name]
+		if !ok {
+			return fmt.Errorf("directive %q is not declared in the schema", name)
+		}
+		if !decl.HasLocation("FIELD_DEFINITION") {
+			return fmt.Errorf("directive %q must be declared on FIELD_DEFINITION", name)
+		}
+
+		t := reflect.TypeOf(impl)
+		if t == nil || t.Kind() != reflect.Func || t.NumOut() != 2 || t.Out(0) != emptyInterfaceType || t.Out(1) != errorInterfaceType {
+			return fmt.Errorf("implementation of directive %q must be of the form %s", name, directiveImplFormat)
+		}
+		in := []reflect.Type{contextType}
+		if len(decl.Args) != 0 && t.NumIn() > 1 {
+			argTypes[name] = t.In(1)
+			in = append(in, t.In(1))
+		}
+		in = append(in, fieldInfoType, fieldResolverType)
+		if t.NumIn() != len(in) {
+			return fmt.Errorf("implementation of directive %q must be of the form %s", name, directiveImplFormat)
+		}
+		for i, want := range in {
+			if t.In(i) != want {
+				return fmt.Errorf("implementation of directive %q must be of the form %s", name, directiveImplFormat)
+			}
+		}
+	}
+
+	b := packer.NewBuilder(s.scalarCodecs)
+	argPackers := make(map[string]*packer.StructPacker)
+	for name, typ := range argTypes {
+		p, err := b.MakeStructPacker(s.schema.Directives[name].Args, typ)
+		if err != nil {
+			return fmt.Errorf("arguments of directive %q: %s", name, err)
+		}
+		argPackers[name] = p
+	}
+	if err := b.Finish(); err != nil {
+		return err
+	}
+
+	s.fieldDirectives = make(map[fieldKey][]FieldMiddleware)
+	for _, t := range s.schema.Types {
+		var typeName string
+		var fields schema.FieldList
+		switch t := t.(type) {
+		case *schema.Object:
+			typeName, fields = t.Name, t.Fields
+		case *schema.Interface:
+			typeName, fields = t.Name, t.Fields
+		default:
+			continue
+		}
+
+		for _, f := range fields {
+			for _, d := range f.Directives {
+				impl, ok := s.directiveImpls[d.Name.Name]
+				if !ok {
+					continue
+				}
+				var packed reflect.Value
+				if p := argPackers[d.Name.Name]; p != nil {
+					var err error
+					packed, err = p.Pack(directiveArgs(d))
+					if err != nil {
+						return fmt.Errorf("directive %q on field %q of type %q: %s", d.Name.Name, f.Name, typeName, err)
+					}
+				}
+				key := fieldKey{typeName, f.Name}
+				s.fieldDirectives[key] = append(s.fieldDirectives[key], directiveMiddleware(reflect.ValueOf(impl), packed))
+			}
+		}
+	}
+	s.inheritDirectives()
+	return nil
+}
+
+// inheritDirectives applies the directives of interface fields to the fields implementing them,
+// so they run no matter through which type the field is selected. Fields with directives of their
+// own keep those.
+func (s *Schema) inheritDirectives() {
+	declared := make(map[fieldKey]bool, len(s.fieldDirectives))
+	for key := range s.fieldDirectives {
+		declared[key] = true
+	}
+
+	for _, t := range s.schema.Types {
+		var typeName string
+		var fields schema.FieldList
+		var intfs []*schema.Interface
+		switch t := t.(type) {
+		case *schema.Object:
+			typeName, fields, intfs = t.Name, t.Fields, t.Interfaces
+		case *schema.Interface:
+			typeName, fields, intfs = t.Name, t.Fields, t.Interfaces
+		default:
+			continue
+		}
+
+		for _, f := range fields {
+			key := fieldKey{typeName, f.Name}
+			if declared[key] {
+				continue
+			}
+			for _, intf := range intfs {
+				if intfKey := (fieldKey{intf.Name, f.Name}); declared[intfKey] {
+					s.fieldDirectives[key] = s.fieldDirectives[intfKey]
+					break
+				}
+			}
+		}
+	}
+}
+
+func directiveMiddleware(impl reflect.Value, packedArgs reflect.Value) FieldMiddleware {
+	return func(ctx context.Context, field *FieldInfo, next FieldResolver) (interface{}, error) {
+		in := []reflect.Value{reflect.ValueOf(ctx)}
+		if packedArgs.IsValid() {
+			in = append(in, packedArgs)
+		}
+		in = append(in, reflect.ValueOf(field), reflect.ValueOf(next))
+		out := impl.Call(in)
+		err, _ := out[1].Interface().(error)
+		return out[0].Interface(), err
+	}
+}
+
+// directiveArgs returns the literals of the directive's arguments, which are packed like the
+// arguments of fields. Missing arguments without a default were added by the schema with a nil
+// value.
+func directiveArgs(d *common.Directive) map[string]interface{} {
+	args := make(map[string]interface{})
+	for _, arg := range d.Args {
+		if arg.Value != nil {
+			args[arg.Name.Name] = packer.Literal(arg.Value, nil)
+		}
+	}
+	return args
+}
@@ -0,0 +1,218 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+)
+
+const directivesSchema = `
+	directive @hasRole(role: String!) on FIELD_DEFINITION
+	directive @uppercase on FIELD_DEFINITION
+
+	schema {
+		query: Query
+	}
+
+	type Query {
+		greeting: String! @uppercase
+		secret: String @hasRole(role: "admin") @uppercase
+	}
+`
+
+type directivesResolver struct{}
+
+func (r *directivesResolver) Greeting() string { return "hello" }
+
+func (r *directivesResolver) Secret() *string {
+	s := "secret"
+	return &s
+}
+
+type roleKey struct{}
+
+func hasRole(ctx context.Context, args *struct{ Role string }, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+	if role, _ := ctx.Value(roleKey{}).(string); role != args.Role {
+		return nil, errors.New("access denied")
+	}
+	return next(ctx)
+}
+
+func uppercase(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+	v, err := next(ctx)
+	switch v := v.(type) {
+	case string:
+		return strings.ToUpper(v), err
+	case *string:
+		if v != nil {
+			u := strings.ToUpper(*v)
+			return &u, err
+		}
+	}
+	return v, err
+}
+
+func TestDirectives(t *testing.T) {
+	var calls []string
+	trace := func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		v, err := next(ctx)
+		if s, ok := v.(*string); ok && s != nil {
+			calls = append(calls, *s)
+		}
+		return v, err
+	}
+	s := graphql.MustParseSchema(directivesSchema, &directivesResolver{},
+		graphql.Directive("hasRole", hasRole),
+		graphql.Directive("uppercase", uppercase),
+		graphql.Middleware(trace),
+	)
+
+	result := s.Exec(context.Background(), `{ greeting secret }`, "", nil)
+	if string(result.Data) != `{"greeting":"HELLO","secret":null}` {
+		t.Errorf("unexpected data %s", result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "access denied" {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+
+	ctx := context.WithValue(context.Background(), roleKey{}, "admin")
+	result = s.Exec(ctx, `{ secret }`, "", nil)
+	if string(result.Data) != `{"secret":"SECRET"}` || len(result.Errors) != 0 {
+		t.Errorf("unexpected result %s %v", result.Data, result.Errors)
+	}
+	if len(calls) != 1 || calls[0] != "SECRET" {
+		t.Errorf("middleware did not see the result of the directives: %q", calls)
+	}
+}
+
+func TestDirectiveErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  graphql.SchemaOpt
+		want string
+	}{
+		{
+			name: "undeclared",
+			opt:  graphql.Directive("lowercase", uppercase),
+			want: `directive "lowercase" is not declared in the schema`,
+		},
+		{
+			name: "missing args",
+			opt:  graphql.Directive("hasRole", uppercase),
+			want: `implementation of directive "hasRole" must be of the form`,
+		},
+		{
+			name: "not a function",
+			opt:  graphql.Directive("uppercase", "upper"),
+			want: `implementation of directive "uppercase" must be of the form`,
+		},
+		{
+			name: "wrong args",
+			opt: graphql.Directive("hasRole", func(ctx context.Context, args *struct{ Name string }, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+				return next(ctx)
+			}),
+			want: `arguments of directive "hasRole"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseSchema(directivesSchema, &directivesResolver{}, tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectiveCustomScalarArgs(t *testing.T) {
+	textCodec := graphql.ScalarCodec{
+		ParseValue: func(value interface{}) (interface{}, error) {
+			return fmt.Sprintf("value %v", value), nil
+		},
+		ParseLiteral: func(value interface{}, text string) (interface{}, error) {
+			return "literal " + text, nil
+		},
+	}
+	suffix := func(ctx context.Context, args *struct{ Text string }, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		v, err := next(ctx)
+		return v.(string) + " " + args.Text, err
+	}
+	s := graphql.MustParseSchema(`
+		directive @suffix(text: Text!) on FIELD_DEFINITION
+
+		scalar Text
+
+		schema {
+			query: Query
+		}
+
+		type Query {
+			greeting: String! @suffix(text: 1.50)
+		}
+	`, &directivesResolver{},
+		graphql.CustomScalar("Text", textCodec),
+		graphql.Directive("suffix", suffix),
+	)
+
+	result := s.Exec(context.Background(), `{ greeting }`, "", nil)
+	if string(result.Data) != `{"greeting":"hello literal 1.50"}` {
+		t.Errorf("unexpected result %s %v", result.Data, result.Errors)
+	}
+}
+
+type namedResolver struct{ name string }
+
+func (r *namedResolver) Name() string { return r.name }
+
+func (r *namedResolver) ToDroid() (*namedResolver, bool) { return r, r.name == "r2-d2" }
+
+func (r *namedResolver) ToHuman() (*namedResolver, bool) { return r, r.name != "r2-d2" }
+
+type interfaceDirectivesResolver struct{}
+
+func (r *interfaceDirectivesResolver) Characters() []*namedResolver {
+	return []*namedResolver{{"r2-d2"}, {"luke"}}
+}
+
+func TestInterfaceFieldDirectives(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		directive @uppercase on FIELD_DEFINITION
+		directive @exclaim on FIELD_DEFINITION
+
+		schema {
+			query: Query
+		}
+
+		type Query {
+			characters: [Character!]!
+		}
+
+		interface Character {
+			name: String! @uppercase
+		}
+
+		type Droid implements Character {
+			name: String!
+		}
+
+		type Human implements Character {
+			name: String! @exclaim
+		}
+	`, &interfaceDirectivesResolver{},
+		graphql.Directive("uppercase", uppercase),
+		graphql.Directive("exclaim", func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+			v, err := next(ctx)
+			return v.(string) + "!", err
+		}),
+	)
+
+	result := s.Exec(context.Background(), `{ characters { name ... on Droid { droidName: name } ... on Human { humanName: name } } }`, "", nil)
+	want := `{"characters":[{"name":"R2-D2","droidName":"R2-D2"},{"name":"LUKE","humanName":"luke!"}]}`
+	if string(result.Data) != want || len(result.Errors) != 0 {
+		t.Errorf("got %s %v, want %s", result.Data, result.Errors, want)
+	}
+}
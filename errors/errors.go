@@ -2,25 +2,40 @@ package errors
 
 import (
 	"fmt"
+	"strings"
 )
 
 type QueryError struct {
-	Message       string        `json:"message"`
-	Locations     []Location    `json:"locations,omitempty"`
-	Path          []interface{} `json:"path,omitempty"`
-	Rule          string        `json:"-"`
-	ResolverError error         `json:"-"`
+	Message       string                 `json:"message"`
+	Locations     []Location             `json:"locations,omitempty"`
+	Path          []interface{}          `json:"path,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+	Rule          string                 `json:"-"`
+	ResolverError error                  `json:"-"`
 }
 
 type Location struct {
 	Line   int `json:"line"`
 	Column int `json:"column"`
+	// Source is the name of the schema source, e.g. a file name. It is empty for queries and
+	// schemas parsed from a single string.
+	Source string `json:"source,omitempty"`
 }
 
 func (a Location) Before(b Location) bool {
+	if a.Source != b.Source {
+		return a.Source < b.Source
+	}
 	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
 }
 
+func (a Location) String() string {
+	if a.Source != "" {
+		return fmt.Sprintf("%s: line %d, column %d", a.Source, a.Line, a.Column)
+	}
+	return fmt.Sprintf("line %d, column %d", a.Line, a.Column)
+}
+
 func Errorf(format string, a ...interface{}) *QueryError {
 	return &QueryError{
 		Message: fmt.Sprintf(format, a...),
@@ -33,9 +48,20 @@ func (err *QueryError) Error() string {
 	}
 	str := fmt.Sprintf("graphql: %s", err.Message)
 	for _, loc := range err.Locations {
-		str += fmt.Sprintf(" (line %d, column %d)", loc.Line, loc.Column)
+		str += " (" + loc.String() + ")"
 	}
 	return str
 }
 
 var _ error = &QueryError{}
+
+// SchemaErrors lists all problems found in a schema.
+type SchemaErrors []*QueryError
+
+func (errs SchemaErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+type extensionsKey struct{}
+
+// responseExtensions collects the extension entries of a request. It is safe for concurrent use,
+// since the resolvers of a request run in parallel.
+type responseExtensions struct {
+	mu      sync.Mutex
+	entries map[string]interface{}
+}
+
+// withExtensions returns a context collecting extension entries, starting with the given ones.
+func withExtensions(ctx context.Context, initial map[string]interface{}) (context.Context, *responseExtensions) {
+	e := &responseExtensions{entries: make(map[string]interface{})}
+	for k, v := range initial {
+		e.entries[k] = v
+	}
+	return context.WithValue(ctx, extensionsKey{}, e), e
+}
+
+// take returns the entries collected so far, or nil if there are none, and starts over.
+func (e *responseExtensions) take() map[string]interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.entries) == 0 {
+		return nil
+	}
+	entries := e.entries
+	e.entries = make(map[string]interface{})
+	return entries
+}
+
+// SetExtension sets an entry of the Extensions of the Response to the request the context belongs
+// to. It may be called by resolvers, tracers and middleware. Values have to be encodable as JSON.
+// Calls with a context which does not belong to a request are ignored.
+//
+// Entries are added to the next Response sent, so for subscriptions and incremental delivery the
+// Response they end up in depends on timing.
+func SetExtension(ctx context.Context, key string, value interface{}) {
+	UpdateExtension(ctx, key, func(interface{}) interface{} {
+		return value
+	})
+}
+
+// UpdateExtension replaces an entry of the Extensions with the result of update, which gets the
+// current value or nil. The update is atomic, so concurrent resolvers may use it to merge their
+// entries, e.g. to append warnings or to sum up costs. If update returns nil, then the entry is
+// removed. It must not call SetExtension or UpdateExtension itself.
+func UpdateExtension(ctx context.Context, key string, update func(value interface{}) interface{}) {
+	e, ok := ctx.Value(extensionsKey{}).(*responseExtensions)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if v := update(e.entries[key]); v != nil {
+		e.entries[key] = v
+	} else {
+		delete(e.entries, key)
+	}
+}
@@ -0,0 +1,88 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/introspection"
+	"github.com/sevlyar/graphql-go/trace"
+)
+
+type warningResolver struct{}
+
+func (r *warningResolver) Items() []*warningItem {
+	l := make([]*warningItem, 10)
+	for i := range l {
+		l[i] = &warningItem{}
+	}
+	return l
+}
+
+type warningItem struct{}
+
+func (r *warningItem) Name(ctx context.Context) string {
+	graphql.UpdateExtension(ctx, "warnings", func(v interface{}) interface{} {
+		warnings, _ := v.([]string)
+		return append(warnings, "name is deprecated")
+	})
+	return "item"
+}
+
+type extensionTracer struct {
+	trace.NoopTracer
+}
+
+func (extensionTracer) TraceQuery(ctx context.Context, queryString string, operationName string, variables map[string]interface{}, varTypes map[string]*introspection.Type) (context.Context, trace.TraceQueryFinishFunc) {
+	return ctx, func(errs []*errors.QueryError) {
+		graphql.SetExtension(ctx, "traced", true)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		schema {
+			query: Query
+		}
+
+		type Query {
+			items: [Item!]!
+		}
+
+		type Item {
+			name: String!
+		}
+	`, &warningResolver{}, graphql.Tracer(extensionTracer{}), graphql.MaxComplexity(0))
+
+	result := s.Exec(context.Background(), `{ items { name } }`, "", nil)
+	if len(result.Errors) != 0 {
+		t.Fatal(result.Errors[0])
+	}
+
+	warnings, _ := result.Extensions["warnings"].([]string)
+	if len(warnings) != 10 {
+		t.Errorf("got %d warnings, want 10", len(warnings))
+	}
+	if result.Extensions["traced"] != true {
+		t.Error("extension set by tracer is missing")
+	}
+	if result.Extensions["complexity"] != 2 {
+		t.Errorf("got complexity %v, want 2", result.Extensions["complexity"])
+	}
+
+	var keys []string
+	for k := range result.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if data, _ := json.Marshal(keys); string(data) != `["complexity","traced","warnings"]` {
+		t.Errorf("unexpected extensions %s", data)
+	}
+}
+
+func TestSetExtensionOutsideOfRequest(t *testing.T) {
+	graphql.SetExtension(context.Background(), "ignored", true) // must not panic
+}
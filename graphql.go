@@ -5,12 +5,14 @@ import (
 	"fmt"
 
 	"encoding/json"
+	"io/fs"
 
 	"strconv"
 
 	"github.com/sevlyar/graphql-go/errors"
 	"github.com/sevlyar/graphql-go/internal/common"
 	"github.com/sevlyar/graphql-go/internal/exec"
+	"github.com/sevlyar/graphql-go/internal/exec/packer"
 	"github.com/sevlyar/graphql-go/internal/exec/resolvable"
 	"github.com/sevlyar/graphql-go/internal/exec/selected"
 	"github.com/sevlyar/graphql-go/internal/query"
@@ -46,28 +48,51 @@ func (id ID) MarshalJSON() ([]byte, error) {
 // the Go type signature of the resolvers does not match the schema. If nil is passed as the
 // resolver, then the schema can not be executed, but it may be inspected (e.g. with ToJSON).
 func ParseSchema(schemaString string, resolver interface{}, opts ...SchemaOpt) (*Schema, error) {
-	s := &Schema{
-		schema:         schema.New(),
-		maxParallelism: 10,
-		tracer:         trace.OpenTracingTracer{},
-		logger:         &log.DefaultLogger{},
-	}
-	for _, opt := range opts {
-		opt(s)
-	}
-
+	s := newSchema(opts)
 	if err := s.schema.Parse(schemaString); err != nil {
 		return nil, err
 	}
+	if err := s.prepare(resolver); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
-	if resolver != nil {
-		r, err := resolvable.ApplyResolver(s.schema, resolver)
+// ParseSchemaFiles parses the files of fsys which match any of the patterns as one schema, see
+// fs.Glob for the syntax of patterns. Each file may extend the types defined in the others. The
+// locations of errors carry the name of the file as their source. The resolver and options work
+// like with ParseSchema.
+func ParseSchemaFiles(fsys fs.FS, patterns []string, resolver interface{}, opts ...SchemaOpt) (*Schema, error) {
+	var sources []schema.Source
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
 		if err != nil {
 			return nil, err
 		}
-		s.res = r
+		if len(names) == 0 {
+			return nil, errors.Errorf("pattern %q matches no schema files", pattern)
+		}
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			body, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, schema.Source{Name: name, Body: string(body)})
+		}
 	}
 
+	s := newSchema(opts)
+	if err := s.schema.ParseSources(sources...); err != nil {
+		return nil, err
+	}
+	if err := s.prepare(resolver); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
@@ -80,14 +105,64 @@ func MustParseSchema(schemaString string, resolver interface{}, opts ...SchemaOp
 	return s
 }
 
+func newSchema(opts []SchemaOpt) *Schema {
+	s := &Schema{
+		schema:         schema.New(),
+		maxParallelism: 10,
+		tracer:         trace.OpenTracingTracer{},
+		logger:         &log.DefaultLogger{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// prepare prepares a parsed schema for execution.
+func (s *Schema) prepare(resolver interface{}) error {
+	if err := s.checkScalarCodecs(); err != nil {
+		return err
+	}
+
+	if err := s.applyDirectives(); err != nil {
+		return err
+	}
+
+	if s.trustedManifest != nil {
+		if err := s.loadTrustedDocuments(); err != nil {
+			return err
+		}
+	}
+
+	if resolver != nil {
+		r, err := resolvable.ApplyResolver(s.schema, resolver, s.scalarCodecs)
+		if err != nil {
+			return err
+		}
+		s.res = r
+	}
+	return nil
+}
+
 // Schema represents a GraphQL schema with an optional resolver.
 type Schema struct {
 	schema *schema.Schema
 	res    *resolvable.Schema
 
 	maxParallelism int
+	maxDepth       int
+	complexity     bool
+	maxComplexity  int
 	tracer         trace.Tracer
 	logger         log.Logger
+	queryCache     *queryCache
+
+	middlewares     []FieldMiddleware
+	directiveImpls  map[string]interface{}
+	fieldDirectives map[fieldKey][]FieldMiddleware
+	trustedManifest []byte
+	trusted         *trustedDocuments
+	scalarCodecs    packer.ScalarCodecs
 }
 
 // SchemaOpt is an option to pass to ParseSchema or MustParseSchema.
@@ -100,6 +175,28 @@ func MaxParallelism(n int) SchemaOpt {
 	}
 }
 
+// MaxDepth specifies the maximum field nesting depth of a query, fragment spreads are counted as if
+// their selections were inlined. The default is 0 which disables the check.
+func MaxDepth(n int) SchemaOpt {
+	return func(s *Schema) {
+		s.maxDepth = n
+	}
+}
+
+// MaxComplexity enables the complexity analysis of queries, see validation.Complexity for how the
+// cost of a query is computed. Queries whose cost exceeds n are rejected before execution, unless n
+// is 0. The cost is reported as "complexity" in the Extensions of the Response.
+//
+// Fields declare their cost with the @cost directive, which has to be declared in the schema:
+//
+//	directive @cost(complexity: Int = 1, multipliers: [String!]) on FIELD_DEFINITION
+func MaxComplexity(n int) SchemaOpt {
+	return func(s *Schema) {
+		s.complexity = true
+		s.maxComplexity = n
+	}
+}
+
 // Tracer is used to trace queries and fields. It defaults to trace.OpenTracingTracer.
 func Tracer(tracer trace.Tracer) SchemaOpt {
 	return func(s *Schema) {
@@ -116,25 +213,28 @@ func Logger(logger log.Logger) SchemaOpt {
 
 // Response represents a typical response of a GraphQL server. It may be encoded to JSON directly or
 // it may be further processed to a custom response type, for example to include custom error data.
+//
+// Items, Path, Label and HasNext are only set by ExecIncremental, see there.
 type Response struct {
 	Data       json.RawMessage        `json:"data,omitempty"`
+	Items      json.RawMessage        `json:"items,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Label      string                 `json:"label,omitempty"`
 	Errors     []*errors.QueryError   `json:"errors,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	HasNext    *bool                  `json:"hasNext,omitempty"`
 }
 
 // Validate validates the given query with the schema.
 func (s *Schema) Validate(queryString string) []*errors.QueryError {
-	doc, qErr := query.Parse(queryString)
-	if qErr != nil {
-		return []*errors.QueryError{qErr}
-	}
-
-	return validation.Validate(s.schema, doc)
+	_, errs := s.parseAndValidate(queryString)
+	return errs
 }
 
 // Exec executes the given query with the schema's resolver. It panics if the schema was created
 // without a resolver. If the context get cancelled, no further resolvers will be called and a
-// the context error will be returned as soon as possible (not immediately).
+// the context error will be returned as soon as possible (not immediately). Subscriptions whose
+// resolver returns a channel are rejected, they have to be executed with Subscribe.
 func (s *Schema) Exec(ctx context.Context, queryString string, operationName string, variables map[string]interface{}) *Response {
 	if s.res == nil {
 		panic("schema created without resolver, can not exec")
@@ -143,12 +243,7 @@ func (s *Schema) Exec(ctx context.Context, queryString string, operationName str
 }
 
 func (s *Schema) exec(ctx context.Context, queryString string, operationName string, variables map[string]interface{}, res *resolvable.Schema) *Response {
-	doc, qErr := query.Parse(queryString)
-	if qErr != nil {
-		return &Response{Errors: []*errors.QueryError{qErr}}
-	}
-
-	errs := validation.Validate(s.schema, doc)
+	doc, errs := s.parseAndValidate(queryString)
 	if len(errs) != 0 {
 		return &Response{Errors: errs}
 	}
@@ -158,16 +253,35 @@ func (s *Schema) exec(ctx context.Context, queryString string, operationName str
 		return &Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}}
 	}
 
-	r := &exec.Request{
+	extensions, qErr := s.checkComplexity(doc, op, variables)
+	if qErr != nil {
+		return &Response{Errors: []*errors.QueryError{qErr}, Extensions: extensions}
+	}
+
+	ctx, ext := withExtensions(ctx, extensions)
+	resp := s.execOperation(ctx, doc, op, queryString, operationName, variables, res)
+	resp.Extensions = ext.take()
+	return resp
+}
+
+func (s *Schema) newRequest(doc *query.Document, variables map[string]interface{}) *exec.Request {
+	return &exec.Request{
 		Request: selected.Request{
 			Doc:    doc,
 			Vars:   variables,
 			Schema: s.schema,
 		},
-		Limiter: make(chan struct{}, s.maxParallelism),
-		Tracer:  s.tracer,
-		Logger:  s.logger,
+		Limiter:    make(chan struct{}, s.maxParallelism),
+		Tracer:     s.tracer,
+		Logger:     s.logger,
+		Middleware: s.execMiddleware(),
+		Codecs:     s.scalarCodecs,
 	}
+}
+
+// execOperation executes the already validated operation.
+func (s *Schema) execOperation(ctx context.Context, doc *query.Document, op *query.Operation, queryString string, operationName string, variables map[string]interface{}, res *resolvable.Schema) *Response {
+	r := s.newRequest(doc, variables)
 	varTypes := make(map[string]*introspection.Type)
 	for _, v := range op.Vars {
 		t, err := common.ResolveType(v.Type, s.schema.Resolve)
@@ -186,6 +300,23 @@ func (s *Schema) exec(ctx context.Context, queryString string, operationName str
 	}
 }
 
+// checkComplexity computes the cost of the operation if enabled with MaxComplexity. It returns the
+// extensions reporting the cost and an error if the cost is too high.
+func (s *Schema) checkComplexity(doc *query.Document, op *query.Operation, variables map[string]interface{}) (map[string]interface{}, *errors.QueryError) {
+	if !s.complexity {
+		return nil, nil
+	}
+
+	cost := validation.Complexity(s.schema, doc, op, variables)
+	extensions := map[string]interface{}{"complexity": cost}
+	if s.maxComplexity > 0 && cost > s.maxComplexity {
+		err := errors.Errorf("query has complexity %d, which exceeds the maximum of %d", cost, s.maxComplexity)
+		err.Rule = "MaxComplexity"
+		return extensions, err
+	}
+	return extensions, nil
+}
+
 func getOperation(document *query.Document, operationName string) (*query.Operation, error) {
 	if len(document.Operations) == 0 {
 		return nil, fmt.Errorf("no operations in query document")
@@ -2,12 +2,15 @@ package graphql_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/sevlyar/graphql-go"
 	"github.com/sevlyar/graphql-go/example/starwars"
 	"github.com/sevlyar/graphql-go/gqltesting"
+	"github.com/sevlyar/graphql-go/scalars"
 )
 
 type helloWorldResolver1 struct{}
@@ -1191,7 +1194,7 @@ func TestIntrospection(t *testing.T) {
 					"b": {
 						"name": "Character",
 						"kind": "INTERFACE",
-						"interfaces": null,
+						"interfaces": [],
 						"possibleTypes": [
 							{
 								"name": "Human"
@@ -1376,6 +1379,35 @@ func TestIntrospection(t *testing.T) {
 				{
 						"__schema": {
 							"directives": [
+								{
+									"name": "defer",
+									"description": "Directs the executor to deliver this fragment in a subsequent payload, if the request is\nexecuted incrementally.",
+									"locations": [
+										"FRAGMENT_SPREAD",
+										"INLINE_FRAGMENT"
+									],
+									"args": [
+										{
+											"name": "if",
+											"description": "Deferred when true.",
+											"type": {
+												"kind": "NON_NULL",
+												"ofType": {
+													"kind": "SCALAR",
+													"name": "Boolean"
+												}
+											}
+										},
+										{
+											"name": "label",
+											"description": "Identifies the payloads of this fragment.",
+											"type": {
+												"kind": "SCALAR",
+												"ofType": null
+											}
+										}
+									]
+								},
 								{
 									"name": "deprecated",
 									"description": "Marks an element of a GraphQL schema as no longer supported.",
@@ -1437,6 +1469,62 @@ func TestIntrospection(t *testing.T) {
 											}
 										}
 									]
+								},
+								{
+									"name": "specifiedBy",
+									"description": "Exposes a URL that specifies the behavior of this scalar.",
+									"locations": [
+										"SCALAR"
+									],
+									"args": [
+										{
+											"name": "url",
+											"description": "The URL that specifies the behavior of this scalar.",
+											"type": {
+												"kind": "NON_NULL",
+												"ofType": {
+													"kind": "SCALAR",
+													"name": "String"
+												}
+											}
+										}
+									]
+								},
+								{
+									"name": "stream",
+									"description": "Directs the executor to deliver the items of this list field in subsequent payloads, if the\nrequest is executed incrementally.",
+									"locations": [
+										"FIELD"
+									],
+									"args": [
+										{
+											"name": "if",
+											"description": "Streamed when true.",
+											"type": {
+												"kind": "NON_NULL",
+												"ofType": {
+													"kind": "SCALAR",
+													"name": "Boolean"
+												}
+											}
+										},
+										{
+											"name": "label",
+											"description": "Identifies the payloads of this field.",
+											"type": {
+												"kind": "SCALAR",
+												"ofType": null
+											}
+										},
+										{
+											"name": "initialCount",
+											"description": "The number of items delivered with the initial payload.",
+											"type": {
+												"kind": "SCALAR",
+												"ofType": null
+											}
+										}
+									]
 								}
 							]
 						}
@@ -1765,3 +1853,176 @@ func TestComposedFragments(t *testing.T) {
 		},
 	})
 }
+
+type nullPropagationResolver struct{}
+
+func (r *nullPropagationResolver) Nullable() *nullPropagationObject {
+	return &nullPropagationObject{}
+}
+
+func (r *nullPropagationResolver) List() *[]*nullPropagationObject {
+	return &[]*nullPropagationObject{{}}
+}
+
+func (r *nullPropagationResolver) NonNullRoot() *nullPropagationObject {
+	return &nullPropagationObject{}
+}
+
+type nullPropagationObject struct{}
+
+func (r *nullPropagationObject) Ok() string {
+	return "ok"
+}
+
+func (r *nullPropagationObject) Fail() (string, error) {
+	return "", errors.New("resolver failed")
+}
+
+func (r *nullPropagationObject) NilObj() *nullPropagationObject {
+	return nil
+}
+
+func (r *nullPropagationObject) Maybe() (*string, error) {
+	return nil, errors.New("maybe failed")
+}
+
+func (r *nullPropagationObject) Meta() scalars.JSON {
+	return nil
+}
+
+func TestNullPropagation(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		schema {
+			query: Query
+		}
+
+		type Query {
+			nullable: Obj
+			list: [Obj!]
+			nonNullRoot: Obj!
+		}
+
+		type Obj {
+			ok: String!
+			fail: String!
+			nilObj: Obj!
+			maybe: String
+			meta: JSON!
+		}
+
+		scalar JSON
+	`, &nullPropagationResolver{})
+
+	tests := []struct {
+		query     string
+		data      string
+		errorPath string
+	}{
+		{
+			query:     `{ nullable { ok fail } }`,
+			data:      `{"nullable":null}`,
+			errorPath: `[nullable fail]`,
+		},
+		{
+			query:     `{ nullable { ok maybe } }`,
+			data:      `{"nullable":{"ok":"ok","maybe":null}}`,
+			errorPath: `[nullable maybe]`,
+		},
+		{
+			query:     `{ list { ok fail } }`,
+			data:      `{"list":null}`,
+			errorPath: `[list 0 fail]`,
+		},
+		{
+			query:     `{ nullable { ok meta } }`,
+			data:      `{"nullable":null}`,
+			errorPath: `[nullable meta]`,
+		},
+		{
+			query:     `{ ok: nullable { ok } nonNullRoot { nilObj { ok } } }`,
+			data:      `null`,
+			errorPath: `[nonNullRoot nilObj]`,
+		},
+	}
+
+	for _, test := range tests {
+		result := s.Exec(context.Background(), test.query, "", nil)
+		if string(result.Data) != test.data {
+			t.Errorf("%s: got data %s, want %s", test.query, result.Data, test.data)
+		}
+		if len(result.Errors) != 1 {
+			t.Errorf("%s: got %d errors, want exactly one", test.query, len(result.Errors))
+			continue
+		}
+		if path := fmt.Sprint(result.Errors[0].Path); path != test.errorPath {
+			t.Errorf("%s: got error path %s, want %s", test.query, path, test.errorPath)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.MaxDepth(3))
+
+	tests := []struct {
+		query    string
+		errorMsg string
+		line     int
+		column   int
+	}{
+		{
+			query: `{ hero { friends { name } } }`,
+		},
+		{
+			query:    `{ hero { friends { friends { name } } } }`,
+			errorMsg: `Field "name" exceeds the maximum query depth of 3.`,
+			line:     1,
+			column:   30,
+		},
+		{
+			query: `
+				{ hero { ...friendNames } }
+				fragment friendNames on Character { friends { ... on Human { name } } }
+			`,
+		},
+		{
+			query: `
+				{ hero { ...friendsOfFriends } }
+				fragment friendsOfFriends on Character { friends { ...friends } }
+				fragment friends on Character { friends { id } }
+			`,
+			errorMsg: `Field "id" exceeds the maximum query depth of 3.`,
+			line:     4,
+			column:   47,
+		},
+	}
+
+	for _, test := range tests {
+		result := s.Exec(context.Background(), test.query, "", nil)
+		if test.errorMsg == "" {
+			if len(result.Errors) != 0 {
+				t.Errorf("%s: unexpected error %s", test.query, result.Errors[0])
+			}
+			continue
+		}
+		if len(result.Errors) != 1 {
+			t.Errorf("%s: got %d errors, want exactly one", test.query, len(result.Errors))
+			continue
+		}
+		err := result.Errors[0]
+		if err.Message != test.errorMsg || err.Rule != "MaxDepth" {
+			t.Errorf("%s: got error %q with rule %q, want %q", test.query, err.Message, err.Rule, test.errorMsg)
+		}
+		if len(err.Locations) != 1 || err.Locations[0].Line != test.line || err.Locations[0].Column != test.column {
+			t.Errorf("%s: got locations %v, want %d:%d", test.query, err.Locations, test.line, test.column)
+		}
+	}
+
+	result := s.Exec(context.Background(), `{ hero { friends { friends { name } } unknown } }`, "", nil)
+	var rules []string
+	for _, err := range result.Errors {
+		rules = append(rules, err.Rule)
+	}
+	if fmt.Sprint(rules) != "[MaxDepth FieldsOnCorrectType]" {
+		t.Errorf("got errors %v, want the depth and the unknown field reported", result.Errors)
+	}
+}
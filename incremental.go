@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/introspection"
+)
+
+// ExecIncremental executes the given query like Exec, but honours the @defer and @stream
+// directives. Exec ignores them and returns the complete result at once.
+//
+// The first Response holds the initial payload. Its HasNext is nil if nothing was deferred, so the
+// query completed with this Response. Otherwise it is followed by one Response per deferred
+// fragment and per streamed list item. Deferred fragments set Data and streamed items set Items,
+// both together with the Path (omitted for the root) and Label of the directive. HasNext is false
+// on the last Response. The returned channel is closed afterwards or when the context gets
+// cancelled. Like Exec, it rejects subscriptions whose resolver returns a channel and panics if the
+// schema was created without a resolver.
+func (s *Schema) ExecIncremental(ctx context.Context, queryString string, operationName string, variables map[string]interface{}) <-chan *Response {
+	if s.res == nil {
+		panic("schema created without resolver, can not exec")
+	}
+
+	doc, errs := s.parseAndValidate(queryString)
+	if len(errs) != 0 {
+		return sendAndReturnClosed(&Response{Errors: errs})
+	}
+
+	op, err := getOperation(doc, operationName)
+	if err != nil {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}})
+	}
+
+	extensions, qErr := s.checkComplexity(doc, op, variables)
+	if qErr != nil {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{qErr}, Extensions: extensions})
+	}
+
+	ctx, ext := withExtensions(ctx, extensions)
+
+	r := s.newRequest(doc, variables)
+	varTypes := make(map[string]*introspection.Type)
+	for _, v := range op.Vars {
+		t, err := common.ResolveType(v.Type, s.schema.Resolve)
+		if err != nil {
+			return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{err}})
+		}
+		varTypes[v.Name.Name] = introspection.WrapType(t)
+	}
+	traceCtx, finish := s.tracer.TraceQuery(ctx, queryString, operationName, variables, varTypes)
+	return forwardResponses(ctx, r.ExecuteIncremental(traceCtx, s.res, op), finish, ext)
+}
@@ -0,0 +1,181 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+)
+
+func collectIncremental(t *testing.T, s *graphql.Schema, query string) []string {
+	var got []string
+	for resp := range s.ExecIncremental(context.Background(), query, "", nil) {
+		data, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(data))
+	}
+	return got
+}
+
+func expectPayloads(t *testing.T, got []string, want ...string) {
+	if len(got) != len(want) {
+		t.Fatalf("got %d payloads %v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("payload %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDefer(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{})
+
+	got := collectIncremental(t, s, `
+		{
+			hero {
+				name
+				... @defer(label: "friends") {
+					friends {
+						name
+					}
+				}
+			}
+		}
+	`)
+	expectPayloads(t, got,
+		`{"data":{"hero":{"name":"R2-D2"}},"hasNext":true}`,
+		`{"data":{"friends":[{"name":"Luke Skywalker"},{"name":"Han Solo"},{"name":"Leia Organa"}]},"path":["hero"],"label":"friends","hasNext":false}`,
+	)
+}
+
+func TestDeferDisabled(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{})
+
+	got := collectIncremental(t, s, `
+		{
+			hero {
+				name
+				... @defer(if: false) {
+					id
+				}
+			}
+		}
+	`)
+	expectPayloads(t, got, `{"data":{"hero":{"name":"R2-D2","id":"2001"}}}`)
+
+	result := s.Exec(context.Background(), `{ hero { name ... @defer { id } } }`, "", nil)
+	if string(result.Data) != `{"hero":{"name":"R2-D2","id":"2001"}}` {
+		t.Errorf("Exec does not ignore @defer, got %s", result.Data)
+	}
+}
+
+func TestStream(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{})
+
+	got := collectIncremental(t, s, `
+		{
+			hero {
+				friends @stream(initialCount: 1, label: "more") {
+					name
+				}
+			}
+		}
+	`)
+	expectPayloads(t, got,
+		`{"data":{"hero":{"friends":[{"name":"Luke Skywalker"}]}},"hasNext":true}`,
+		`{"items":[{"name":"Han Solo"}],"path":["hero","friends",1],"label":"more","hasNext":true}`,
+		`{"items":[{"name":"Leia Organa"}],"path":["hero","friends",2],"label":"more","hasNext":false}`,
+	)
+}
+
+func TestStreamNegativeInitialCount(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{})
+
+	got := collectIncremental(t, s, `{ hero { friends @stream(initialCount: -1) { name } } }`)
+	expectPayloads(t, got, `{"data":{"hero":{"friends":[{"name":"Luke Skywalker"},{"name":"Han Solo"},{"name":"Leia Organa"}]}},"errors":[{"message":"initialCount of @stream must not be negative"}]}`)
+}
+
+type brokenObjectResolver struct{}
+
+func (r *brokenObjectResolver) O() *brokenObjectResolver {
+	return r
+}
+
+func (r *brokenObjectResolver) Name() string {
+	return "n"
+}
+
+func (r *brokenObjectResolver) Broken() (string, error) {
+	return "", errors.New("broken")
+}
+
+func (r *brokenObjectResolver) Items() []string {
+	return []string{"a", "b"}
+}
+
+func TestIncrementalNulledParent(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		schema {
+			query: Query
+		}
+
+		type Query {
+			o: Object
+		}
+
+		type Object {
+			o: Object
+			name: String!
+			broken: String!
+			items: [String!]!
+		}
+	`, &brokenObjectResolver{})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "defer",
+			query: `{ o { broken ... @defer { name } } }`,
+			want: []string{
+				`{"data":{"o":null},"errors":[{"message":"broken","path":["o","broken"]}]}`,
+			},
+		},
+		{
+			name:  "stream",
+			query: `{ o { broken items @stream(initialCount: 0) } }`,
+			want: []string{
+				`{"data":{"o":null},"errors":[{"message":"broken","path":["o","broken"]}]}`,
+			},
+		},
+		{
+			name:  "sibling",
+			query: `{ a: o { o { broken ... @defer { name } } } b: o { ... @defer { name } } }`,
+			want: []string{
+				`{"data":{"a":{"o":null},"b":{}},"errors":[{"message":"broken","path":["a","o","broken"]}],"hasNext":true}`,
+				`{"data":{"name":"n"},"path":["b"],"hasNext":false}`,
+			},
+		},
+		{
+			name:  "nested defer",
+			query: `{ o { ... @defer(label: "outer") { o { broken ... @defer(label: "inner") { name } } } } }`,
+			want: []string{
+				`{"data":{"o":{}},"hasNext":true}`,
+				`{"data":{"o":null},"path":["o"],"label":"outer","errors":[{"message":"broken","path":["o","o","broken"]}],"hasNext":false}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectPayloads(t, collectIncremental(t, s, tt.query), tt.want...)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package graphql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const interfacesSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		node: Node!
+		entities: [Entity!]!
+	}
+
+	interface Entity {
+		id: ID!
+	}
+
+	interface Timestamped {
+		createdAt: String!
+	}
+
+	interface Node implements Entity & Timestamped {
+		id: ID!
+		createdAt: String!
+		title: String!
+	}
+
+	type Post implements Node & Entity & Timestamped {
+		id: ID!
+		createdAt: String!
+		title: String!
+		body: String!
+	}
+
+	type Tag implements Entity {
+		id: ID!
+		label: String!
+	}
+`
+
+type interfacesResolver struct{}
+
+func (r *interfacesResolver) Node() *nodeResolver {
+	return &nodeResolver{&postResolver{}}
+}
+
+func (r *interfacesResolver) Entities() []*entityResolver {
+	return []*entityResolver{{&postResolver{}}, {&tagResolver{}}}
+}
+
+type entity interface {
+	ID() graphql.ID
+}
+
+type entityResolver struct {
+	entity
+}
+
+func (r *entityResolver) ToPost() (*postResolver, bool) {
+	p, ok := r.entity.(*postResolver)
+	return p, ok
+}
+
+func (r *entityResolver) ToTag() (*tagResolver, bool) {
+	t, ok := r.entity.(*tagResolver)
+	return t, ok
+}
+
+type nodeResolver struct {
+	*postResolver
+}
+
+func (r *nodeResolver) ToPost() (*postResolver, bool) {
+	return r.postResolver, true
+}
+
+type postResolver struct{}
+
+func (r *postResolver) ID() graphql.ID    { return "p1" }
+func (r *postResolver) CreatedAt() string { return "2017-01-01" }
+func (r *postResolver) Title() string     { return "Hello" }
+func (r *postResolver) Body() string      { return "World" }
+
+type tagResolver struct{}
+
+func (r *tagResolver) ID() graphql.ID { return "t1" }
+func (r *tagResolver) Label() string  { return "news" }
+
+func TestInterfacesImplementingInterfaces(t *testing.T) {
+	s := graphql.MustParseSchema(interfacesSchema, &interfacesResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				{
+					node {
+						... on Entity {
+							id
+						}
+						...Stamp
+						title
+						... on Post {
+							body
+						}
+					}
+					entities {
+						__typename
+						... on Node {
+							title
+						}
+						... on Tag {
+							label
+						}
+					}
+				}
+
+				fragment Stamp on Timestamped {
+					createdAt
+				}
+			`,
+			ExpectedResult: `
+				{
+					"node": {
+						"id": "p1",
+						"createdAt": "2017-01-01",
+						"title": "Hello",
+						"body": "World"
+					},
+					"entities": [
+						{"__typename": "Post", "title": "Hello"},
+						{"__typename": "Tag", "label": "news"}
+					]
+				}
+			`,
+		},
+		{
+			Schema: s,
+			Query: `
+				{
+					node: __type(name: "Node") {
+						interfaces {
+							name
+						}
+						possibleTypes {
+							name
+						}
+					}
+					entity: __type(name: "Entity") {
+						interfaces {
+							name
+						}
+						possibleTypes {
+							name
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"node": {
+						"interfaces": [{"name": "Entity"}, {"name": "Timestamped"}],
+						"possibleTypes": [{"name": "Post"}]
+					},
+					"entity": {
+						"interfaces": [],
+						"possibleTypes": [{"name": "Post"}, {"name": "Tag"}]
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestInterfaceImplementationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "missing transitive interface",
+			schema: `interface A { id: ID! } interface B implements A { id: ID! } type Query implements B { id: ID! }`,
+			want:   `type "Query" must implement "A" because it is implemented by "B"`,
+		},
+		{
+			name:   "self",
+			schema: `interface A implements A { id: ID! } type Query { id: ID! }`,
+			want:   `interface "A" can not implement itself`,
+		},
+		{
+			name:   "cycle",
+			schema: `interface A implements B { id: ID! } interface B implements A { id: ID! } type Query { id: ID! }`,
+			want:   `can not implement itself`,
+		},
+		{
+			name:   "missing field",
+			schema: `interface A { id: ID! name: String } interface B implements A { id: ID! } type Query { id: ID! }`,
+			want:   `type "B" does not implement "A": missing field "name"`,
+		},
+		{
+			name:   "incompatible field type",
+			schema: `interface A { id: ID! } interface B implements A { id: ID } type Query { id: ID! }`,
+			want:   `field "id" of type "B" has type "ID" which is not a subtype of "ID!" required by "A"`,
+		},
+		{
+			name:   "missing argument",
+			schema: `interface A { f(x: Int): Int } type Query implements A { f: Int }`,
+			want:   `field "f" of type "Query" must have argument "x" of type "Int" required by "A"`,
+		},
+		{
+			name:   "additional required argument",
+			schema: `interface A { f: Int } type Query implements A { f(x: Int!): Int }`,
+			want:   `field "f" of type "Query" has required argument "x" which is missing on "A"`,
+		},
+		{
+			name:   "not an interface",
+			schema: `type A { id: ID! } interface B implements A { id: ID! } type Query { id: ID! }`,
+			want:   `type "A" is not an interface`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseSchema("schema { query: Query } "+tt.schema, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package common
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"text/scanner"
 
 	"github.com/sevlyar/graphql-go/errors"
@@ -12,6 +14,7 @@ type syntaxError string
 type Lexer struct {
 	sc          *scanner.Scanner
 	next        rune
+	text        string
 	descComment string
 }
 
@@ -50,9 +53,14 @@ func (l *Lexer) Consume() {
 	l.descComment = ""
 	for {
 		l.next = l.sc.Scan()
+		l.text = l.sc.TokenText()
 		if l.next == ',' {
 			continue
 		}
+		if l.next == scanner.String && l.text == `""` && l.sc.Peek() == '"' {
+			l.sc.Next()
+			l.text = strconv.Quote(blockStringValue(l.consumeBlockString()))
+		}
 		if l.next == '#' {
 			if l.sc.Peek() == ' ' {
 				l.sc.Next()
@@ -74,34 +82,39 @@ func (l *Lexer) Consume() {
 }
 
 func (l *Lexer) ConsumeIdent() string {
-	name := l.sc.TokenText()
+	name := l.text
 	l.ConsumeToken(scanner.Ident)
 	return name
 }
 
 func (l *Lexer) ConsumeIdentWithLoc() Ident {
 	loc := l.Location()
-	name := l.sc.TokenText()
+	name := l.text
 	l.ConsumeToken(scanner.Ident)
 	return Ident{name, loc}
 }
 
 func (l *Lexer) ConsumeKeyword(keyword string) {
-	if l.next != scanner.Ident || l.sc.TokenText() != keyword {
-		l.SyntaxError(fmt.Sprintf("unexpected %q, expecting %q", l.sc.TokenText(), keyword))
+	if !l.PeekKeyword(keyword) {
+		l.SyntaxError(fmt.Sprintf("unexpected %q, expecting %q", l.text, keyword))
 	}
 	l.Consume()
 }
 
+// PeekKeyword reports whether the next token is the given keyword.
+func (l *Lexer) PeekKeyword(keyword string) bool {
+	return l.next == scanner.Ident && l.text == keyword
+}
+
 func (l *Lexer) ConsumeLiteral() *BasicLit {
-	lit := &BasicLit{Type: l.next, Text: l.sc.TokenText()}
+	lit := &BasicLit{Type: l.next, Text: l.text}
 	l.Consume()
 	return lit
 }
 
 func (l *Lexer) ConsumeToken(expected rune) {
 	if l.next != expected {
-		l.SyntaxError(fmt.Sprintf("unexpected %q, expecting %s", l.sc.TokenText(), scanner.TokenString(expected)))
+		l.SyntaxError(fmt.Sprintf("unexpected %q, expecting %s", l.text, scanner.TokenString(expected)))
 	}
 	l.Consume()
 }
@@ -110,6 +123,77 @@ func (l *Lexer) DescComment() string {
 	return l.descComment
 }
 
+// Description consumes the string or block string describing the following definition and
+// returns its value. Without such a string, the comments preceding the definition are used.
+func (l *Lexer) Description() string {
+	if l.next != scanner.String {
+		return l.descComment
+	}
+	desc, err := strconv.Unquote(l.text)
+	if err != nil {
+		l.SyntaxError(fmt.Sprintf("invalid string %s", l.text))
+	}
+	l.Consume()
+	return desc
+}
+
+// consumeBlockString reads the raw content of a block string up to the closing triple quote. The
+// opening triple quote has already been consumed.
+func (l *Lexer) consumeBlockString() string {
+	var raw []rune
+	start := 0 // escaped quotes before start can not close the string
+	for {
+		next := l.sc.Next()
+		if next == scanner.EOF {
+			l.SyntaxError("unterminated block string")
+		}
+		raw = append(raw, next)
+		n := len(raw)
+		if n-start >= 4 && string(raw[n-4:]) == `\"""` {
+			raw = append(raw[:n-4], '"', '"', '"')
+			start = len(raw)
+			continue
+		}
+		if n-start >= 3 && string(raw[n-3:]) == `"""` {
+			return string(raw[:n-3])
+		}
+	}
+}
+
+// blockStringValue removes the common indentation and the leading and trailing blank lines of a
+// block string.
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(raw), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < indent {
+				lines[i] = ""
+			} else {
+				lines[i] = lines[i][indent:]
+			}
+		}
+	}
+
+	for len(lines) != 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (l *Lexer) SyntaxError(message string) {
 	panic(syntaxError(message))
 }
@@ -118,5 +202,6 @@ func (l *Lexer) Location() errors.Location {
 	return errors.Location{
 		Line:   l.sc.Line,
 		Column: l.sc.Column,
+		Source: l.sc.Filename,
 	}
 }
@@ -0,0 +1,255 @@
+package batch
+
+import (
+	"context"
+	"sync"
+)
+
+// Scheduler decides when the batches of a single request are dispatched. It counts the goroutines
+// of the request which are able to make progress. As soon as all of them are blocked waiting for
+// batches, all pending batches are dispatched. This coalesces all keys which are requested during
+// one "tick" of the execution into a single batch, independent of timing.
+type Scheduler struct {
+	ctx      context.Context
+	limiter  chan struct{}
+	detached bool
+
+	mu       sync.Mutex
+	runnable int
+	pending  []*Batch
+
+	stateMu sync.Mutex
+	state   map[interface{}]interface{}
+}
+
+// NewScheduler returns a scheduler for the goroutine calling it. Goroutines holding a slot of the
+// limiter give it up while they are waiting for a batch.
+func NewScheduler(limiter chan struct{}) *Scheduler {
+	return &Scheduler{
+		limiter:  limiter,
+		runnable: 1,
+		state:    make(map[interface{}]interface{}),
+	}
+}
+
+// NewDetachedScheduler returns a scheduler which does not track any goroutines. Its batches are
+// only dispatched explicitly with Fire.
+func NewDetachedScheduler() *Scheduler {
+	return &Scheduler{
+		detached: true,
+		state:    make(map[interface{}]interface{}),
+	}
+}
+
+type schedulerKey struct{}
+
+// WithScheduler returns a context carrying the scheduler. The context is returned by the
+// scheduler's Context method.
+func WithScheduler(ctx context.Context, s *Scheduler) context.Context {
+	ctx = context.WithValue(ctx, schedulerKey{}, s)
+	s.ctx = ctx
+	return ctx
+}
+
+// FromContext returns the scheduler of the request, or nil.
+func FromContext(ctx context.Context) *Scheduler {
+	s, _ := ctx.Value(schedulerKey{}).(*Scheduler)
+	return s
+}
+
+// Context returns the context the scheduler was attached to. It is nil for detached schedulers.
+func (s *Scheduler) Context() context.Context {
+	return s.ctx
+}
+
+// State returns the value stored for key, creating it with newState on first use. It is used to
+// keep request scoped state like caches.
+func (s *Scheduler) State(key interface{}, newState func() interface{}) interface{} {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	v, ok := s.state[key]
+	if !ok {
+		v = newState()
+		s.state[key] = v
+	}
+	return v
+}
+
+// Go runs f for every 0 <= i < n in its own goroutine and waits until all of them are done. The
+// calling goroutine counts as blocked in the meantime.
+func (s *Scheduler) Go(n int, f func(i int)) {
+	if n == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.runnable += n - 1 // n new goroutines, the caller blocks
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var remainingMu sync.Mutex
+	remaining := n
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				remainingMu.Lock()
+				remaining--
+				last := remaining == 0
+				remainingMu.Unlock()
+				if !last {
+					s.leave()
+				}
+				// the last goroutine hands its count over to the caller which continues
+			}()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) leave() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runnable--
+	if s.runnable == 0 {
+		s.flushLocked()
+	}
+}
+
+// Batch is a unit of work which is dispatched once by the scheduler.
+type Batch struct {
+	run       func()
+	done      chan struct{}
+	fired     bool
+	completed bool
+	waiters   int
+}
+
+// NewBatch registers a batch which gets dispatched with the next tick. The run function is
+// executed in its own goroutine.
+func (s *Scheduler) NewBatch(run func()) *Batch {
+	b := &Batch{
+		run:  run,
+		done: make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.pending = append(s.pending, b)
+	s.mu.Unlock()
+	return b
+}
+
+// Fire dispatches the batch immediately, e.g. because it is full. It is a no-op if the batch was
+// already dispatched.
+func (s *Scheduler) Fire(b *Batch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fireLocked(b)
+}
+
+func (s *Scheduler) flushLocked() {
+	pending := s.pending
+	s.pending = nil
+	for _, b := range pending {
+		s.fireLocked(b)
+	}
+}
+
+func (s *Scheduler) fireLocked(b *Batch) {
+	if b.fired {
+		return
+	}
+	b.fired = true
+	for i, p := range s.pending {
+		if p == b {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			break
+		}
+	}
+
+	s.runnable++
+	go func() {
+		defer s.complete(b)
+		b.run()
+	}()
+}
+
+func (s *Scheduler) complete(b *Batch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b.completed = true
+	s.runnable += b.waiters // the waiters continue
+	s.runnable--            // the batch goroutine is done
+	b.waiters = 0
+	close(b.done)
+	if s.runnable == 0 && !s.detached {
+		s.flushLocked()
+	}
+}
+
+// Wait blocks until the batch is completed or the context is done. If the context carries a slot
+// of the limiter, then the slot is given up while waiting.
+func (s *Scheduler) Wait(ctx context.Context, b *Batch) error {
+	s.mu.Lock()
+	if b.completed {
+		s.mu.Unlock()
+		return nil
+	}
+	if !s.detached {
+		b.waiters++
+		s.runnable--
+		if s.runnable == 0 {
+			s.flushLocked()
+		}
+	}
+	s.mu.Unlock()
+
+	if sl, ok := ctx.Value(slotKey{}).(*slot); ok && s.limiter != nil && sl.release() {
+		<-s.limiter
+		defer func() {
+			s.limiter <- struct{}{}
+			sl.acquire()
+		}()
+	}
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !b.completed && !s.detached {
+			b.waiters--
+			s.runnable++
+		}
+		return ctx.Err()
+	}
+}
+
+type slotKey struct{}
+
+type slot struct {
+	mu   sync.Mutex
+	held bool
+}
+
+// WithLimiterSlot marks the context as belonging to a resolver which holds a slot of the limiter.
+func WithLimiterSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, slotKey{}, &slot{held: true})
+}
+
+// release reports whether the slot was held. Only one of several concurrent waiters gives it up.
+func (sl *slot) release() bool {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	held := sl.held
+	sl.held = false
+	return held
+}
+
+func (sl *slot) acquire() {
+	sl.mu.Lock()
+	sl.held = true
+	sl.mu.Unlock()
+}
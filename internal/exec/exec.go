@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
-	"sync"
 
 	"github.com/sevlyar/graphql-go/errors"
 	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/exec/batch"
+	"github.com/sevlyar/graphql-go/internal/exec/packer"
 	"github.com/sevlyar/graphql-go/internal/exec/resolvable"
 	"github.com/sevlyar/graphql-go/internal/exec/selected"
 	"github.com/sevlyar/graphql-go/internal/query"
@@ -17,11 +20,20 @@ import (
 	"github.com/sevlyar/graphql-go/trace"
 )
 
+// Middleware wraps the call of a field's resolver, see graphql.FieldMiddleware.
+type Middleware func(ctx context.Context, typeName string, fieldName string, args map[string]interface{}, path []interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error)
+
 type Request struct {
 	selected.Request
-	Limiter chan struct{}
-	Tracer  trace.Tracer
-	Logger  log.Logger
+	Limiter    chan struct{}
+	Tracer     trace.Tracer
+	Logger     log.Logger
+	Middleware Middleware
+	Codecs     packer.ScalarCodecs
+
+	sched *batch.Scheduler
+	inc   *incremental
+	tasks *taskGroup
 }
 
 type fieldResult struct {
@@ -41,10 +53,18 @@ func makePanicError(value interface{}) *errors.QueryError {
 }
 
 func (r *Request) Execute(ctx context.Context, s *resolvable.Schema, op *query.Operation) ([]byte, []*errors.QueryError) {
+	ctx = r.withScheduler(ctx)
+
 	var out bytes.Buffer
 	func() {
 		defer r.handlePanic(ctx)
 		sels := selected.ApplyOperation(&r.Request, s, op)
+		if op.Type == query.Subscription {
+			if f := streamingField(sels, s.Resolver); f != nil {
+				r.AddError(errors.Errorf("resolver of subscription field %q returns a channel, use Subscribe to execute the operation", f.field.Name))
+				return
+			}
+		}
 		r.execSelections(ctx, sels, nil, s.Resolver, &out, op.Type == query.Mutation)
 	}()
 
@@ -55,31 +75,66 @@ func (r *Request) Execute(ctx context.Context, s *resolvable.Schema, op *query.O
 	return out.Bytes(), r.Errs
 }
 
+// streamingField returns the first root field of a subscription whose resolver returns a channel.
+// Such a field has no single result, so the operation can not be executed like a query.
+func streamingField(sels []selected.Selection, resolver reflect.Value) *fieldToExec {
+	var fields []*fieldToExec
+	collectFieldsToResolve(sels, resolver, &fields, make(map[string]*fieldToExec), nil)
+	for _, f := range fields {
+		if f.field.Streams {
+			return f
+		}
+	}
+	return nil
+}
+
+// withScheduler attaches a new batch scheduler for the goroutine executing the request.
+func (r *Request) withScheduler(ctx context.Context) context.Context {
+	r.sched = batch.NewScheduler(r.Limiter)
+	return batch.WithScheduler(ctx, r.sched)
+}
+
 type fieldToExec struct {
 	field    *selected.SchemaField
 	sels     []selected.Selection
 	resolver reflect.Value
 	out      *bytes.Buffer
+	null     bool
 }
 
-func (r *Request) execSelections(ctx context.Context, sels []selected.Selection, path *pathSegment, resolver reflect.Value, out *bytes.Buffer, serially bool) {
+// execSelections writes the object with the selected fields to out. It reports whether the object
+// became null because a non-null field resolved to null.
+func (r *Request) execSelections(ctx context.Context, sels []selected.Selection, path *pathSegment, resolver reflect.Value, out *bytes.Buffer, serially bool) (null bool) {
 	async := !serially && selected.HasAsyncSel(sels)
 
 	var fields []*fieldToExec
-	collectFieldsToResolve(sels, resolver, &fields, make(map[string]*fieldToExec))
+	var deferred []*deferredFragment
+	collectFieldsToResolve(sels, resolver, &fields, make(map[string]*fieldToExec), &deferred)
+	for _, d := range deferred {
+		r.deferFragment(path, d)
+	}
 
 	if async {
-		var wg sync.WaitGroup
-		wg.Add(len(fields))
+		r.sched.Go(len(fields), func(i int) {
+			defer r.handlePanic(ctx)
+			f := fields[i]
+			f.out = new(bytes.Buffer)
+			f.null = execFieldSelection(ctx, r, f, &pathSegment{path, f.field.Alias}, true)
+		})
+	} else {
 		for _, f := range fields {
-			go func(f *fieldToExec) {
-				defer wg.Done()
-				defer r.handlePanic(ctx)
-				f.out = new(bytes.Buffer)
-				execFieldSelection(ctx, r, f, &pathSegment{path, f.field.Alias}, true)
-			}(f)
+			f.out = new(bytes.Buffer)
+			f.null = execFieldSelection(ctx, r, f, &pathSegment{path, f.field.Alias}, false)
+		}
+	}
+
+	for _, f := range fields {
+		if _, nonNull := f.field.Type.(*common.NonNull); nonNull && f.null {
+			// the error was already recorded, null propagates to the nearest nullable parent
+			out.WriteString("null")
+			r.tasks.setNull(path)
+			return true
 		}
-		wg.Wait()
 	}
 
 	out.WriteByte('{')
@@ -91,17 +146,20 @@ func (r *Request) execSelections(ctx context.Context, sels []selected.Selection,
 		out.WriteString(f.field.Alias)
 		out.WriteByte('"')
 		out.WriteByte(':')
-		if async {
-			out.Write(f.out.Bytes())
-			continue
-		}
-		f.out = out
-		execFieldSelection(ctx, r, f, &pathSegment{path, f.field.Alias}, false)
+		out.Write(f.out.Bytes())
 	}
 	out.WriteByte('}')
+	return false
 }
 
-func collectFieldsToResolve(sels []selected.Selection, resolver reflect.Value, fields *[]*fieldToExec, fieldByAlias map[string]*fieldToExec) {
+type deferredFragment struct {
+	frag     *selected.DeferredFragment
+	resolver reflect.Value
+}
+
+// collectFieldsToResolve flattens the selections for the given resolver. Deferred fragments are
+// added to deferred, or resolved right away if deferred is nil.
+func collectFieldsToResolve(sels []selected.Selection, resolver reflect.Value, fields *[]*fieldToExec, fieldByAlias map[string]*fieldToExec, deferred *[]*deferredFragment) {
 	for _, sel := range sels {
 		switch sel := sel.(type) {
 		case *selected.SchemaField:
@@ -126,7 +184,14 @@ func collectFieldsToResolve(sels []selected.Selection, resolver reflect.Value, f
 			if !out[1].Bool() {
 				continue
 			}
-			collectFieldsToResolve(sel.Sels, out[0], fields, fieldByAlias)
+			collectFieldsToResolve(sel.Sels, out[0], fields, fieldByAlias, deferred)
+
+		case *selected.DeferredFragment:
+			if deferred == nil {
+				collectFieldsToResolve(sel.Sels, resolver, fields, fieldByAlias, deferred)
+				continue
+			}
+			*deferred = append(*deferred, &deferredFragment{frag: sel, resolver: resolver})
 
 		default:
 			panic("unreachable")
@@ -147,7 +212,9 @@ func typeOf(tf *selected.TypenameField, resolver reflect.Value) string {
 	return ""
 }
 
-func execFieldSelection(ctx context.Context, r *Request, f *fieldToExec, path *pathSegment, applyLimiter bool) {
+// execFieldSelection resolves the field and writes its value to f.out. It reports whether the value
+// is null.
+func execFieldSelection(ctx context.Context, r *Request, f *fieldToExec, path *pathSegment, applyLimiter bool) (null bool) {
 	if applyLimiter {
 		r.Limiter <- struct{}{}
 	}
@@ -178,17 +245,18 @@ func execFieldSelection(ctx context.Context, r *Request, f *fieldToExec, path *p
 			return errors.Errorf("%s", err) // don't execute any more resolvers if context got cancelled
 		}
 
-		var in []reflect.Value
-		if f.field.HasContext {
-			in = append(in, reflect.ValueOf(traceCtx))
+		resolverCtx := traceCtx
+		if applyLimiter {
+			resolverCtx = batch.WithLimiterSlot(resolverCtx)
 		}
-		if f.field.ArgsPacker != nil {
-			in = append(in, f.field.PackedArgs)
+
+		var resolverErr error
+		if r.Middleware != nil {
+			result, resolverErr = r.callWithMiddleware(resolverCtx, f, path)
+		} else {
+			result, resolverErr = callResolver(resolverCtx, f)
 		}
-		callOut := f.resolver.Method(f.field.MethodIndex).Call(in)
-		result = callOut[0]
-		if f.field.HasError && !callOut[1].IsNil() {
-			resolverErr := callOut[1].Interface().(error)
+		if resolverErr != nil {
 			err := errors.Errorf("%s", resolverErr)
 			err.Path = path.toSlice()
 			err.ResolverError = resolverErr
@@ -203,92 +271,207 @@ func execFieldSelection(ctx context.Context, r *Request, f *fieldToExec, path *p
 
 	if err != nil {
 		r.AddError(err)
-		f.out.WriteString("null") // TODO handle non-nil
-		return
+		f.out.WriteString("null") // the parent takes care of non-null fields
+		return true
+	}
+
+	if f.field.Stream != nil && r.inc != nil {
+		return r.execStream(traceCtx, f, path, result)
+	}
+	return r.execSelectionSet(traceCtx, f.sels, f.field.Type, path, result, f.out)
+}
+
+// callResolver calls the resolver method of the field.
+func callResolver(ctx context.Context, f *fieldToExec) (reflect.Value, error) {
+	var in []reflect.Value
+	if f.field.HasContext {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+	if f.field.ArgsPacker != nil {
+		in = append(in, f.field.PackedArgs)
+	}
+	callOut := f.resolver.Method(f.field.MethodIndex).Call(in)
+	if f.field.HasError && !callOut[1].IsNil() {
+		return callOut[0], callOut[1].Interface().(error)
 	}
+	return callOut[0], nil
+}
 
-	r.execSelectionSet(traceCtx, f.sels, f.field.Type, path, result, f.out)
+// callWithMiddleware calls the resolver through the middleware. A value returned by the middleware
+// instead of the resolver's one has to be assignable to the resolver's result type.
+func (r *Request) callWithMiddleware(ctx context.Context, f *fieldToExec, path *pathSegment) (reflect.Value, error) {
+	resultType := f.resolver.Method(f.field.MethodIndex).Type().Out(0)
+	v, err := r.Middleware(ctx, f.field.TypeName, f.field.Name, f.field.Args, path.toSlice(), func(ctx context.Context) (interface{}, error) {
+		result, err := callResolver(ctx, f)
+		return result.Interface(), err
+	})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if v == nil {
+		if k := resultType.Kind(); k != reflect.Ptr && k != reflect.Interface {
+			return reflect.Value{}, fmt.Errorf("middleware returned nil for field %q, want %s", f.field.Name, resultType)
+		}
+		return reflect.Zero(resultType), nil
+	}
+	result := reflect.ValueOf(v)
+	if !result.Type().AssignableTo(resultType) {
+		return reflect.Value{}, fmt.Errorf("middleware returned %T for field %q, want %s", v, f.field.Name, resultType)
+	}
+	if result.Type() != resultType {
+		converted := reflect.New(resultType).Elem()
+		converted.Set(result)
+		result = converted
+	}
+	return result, nil
 }
 
-func (r *Request) execSelectionSet(ctx context.Context, sels []selected.Selection, typ common.Type, path *pathSegment, resolver reflect.Value, out *bytes.Buffer) {
+// execSelectionSet writes the value of the given type to out. It reports whether the value is null,
+// an error is recorded if the type is non-null.
+func (r *Request) execSelectionSet(ctx context.Context, sels []selected.Selection, typ common.Type, path *pathSegment, resolver reflect.Value, out *bytes.Buffer) (null bool) {
 	t, nonNull := unwrapNonNull(typ)
 	switch t := t.(type) {
 	case *schema.Object, *schema.Interface, *schema.Union:
-		if resolver.Kind() == reflect.Ptr && resolver.IsNil() {
+		if (resolver.Kind() == reflect.Ptr || resolver.Kind() == reflect.Interface) && resolver.IsNil() {
 			if nonNull {
-				panic(errors.Errorf("got nil for non-null %q", t))
+				err := errors.Errorf("got nil for non-null %q", t)
+				err.Path = path.toSlice()
+				r.AddError(err)
 			}
 			out.WriteString("null")
-			return
+			return true
 		}
 
-		r.execSelections(ctx, sels, path, resolver, out, false)
-		return
+		return r.execSelections(ctx, sels, path, resolver, out, false)
 	}
 
 	if !nonNull {
 		if resolver.IsNil() {
 			out.WriteString("null")
-			return
+			return true
 		}
 		resolver = resolver.Elem()
 	}
 
 	switch t := t.(type) {
 	case *common.List:
-		l := resolver.Len()
-
-		if selected.HasAsyncSel(sels) {
-			var wg sync.WaitGroup
-			wg.Add(l)
-			entryouts := make([]bytes.Buffer, l)
-			for i := 0; i < l; i++ {
-				go func(i int) {
-					defer wg.Done()
-					defer r.handlePanic(ctx)
-					r.execSelectionSet(ctx, sels, t.OfType, &pathSegment{path, i}, resolver.Index(i), &entryouts[i])
-				}(i)
-			}
-			wg.Wait()
-
-			out.WriteByte('[')
-			for i, entryout := range entryouts {
-				if i > 0 {
-					out.WriteByte(',')
-				}
-				out.Write(entryout.Bytes())
-			}
-			out.WriteByte(']')
-			return
-		}
-
-		out.WriteByte('[')
-		for i := 0; i < l; i++ {
-			if i > 0 {
-				out.WriteByte(',')
-			}
-			r.execSelectionSet(ctx, sels, t.OfType, &pathSegment{path, i}, resolver.Index(i), out)
-		}
-		out.WriteByte(']')
+		return r.execList(ctx, sels, t.OfType, path, resolver, resolver.Len(), out)
 
 	case *schema.Scalar:
 		v := resolver.Interface()
-		data, err := json.Marshal(v)
+		var err error
+		if codec := r.Codecs[t.Name]; codec != nil && codec.Serialize != nil {
+			v, err = codec.Serialize(v)
+		} else if t.Name == "Int" {
+			err = checkInt32(resolver)
+		}
+		var data []byte
+		if err == nil {
+			data, err = json.Marshal(v)
+		}
 		if err != nil {
-			panic(errors.Errorf("could not marshal %v", v))
+			qErr := errors.Errorf("could not serialize %s: %s", t.Name, err)
+			qErr.Path = path.toSlice()
+			qErr.ResolverError = err
+			r.AddError(qErr)
+			out.WriteString("null")
+			return true
 		}
 		out.Write(data)
+		if bytes.Equal(data, []byte("null")) {
+			if nonNull {
+				err := errors.Errorf("got null for non-null %q", t)
+				err.Path = path.toSlice()
+				r.AddError(err)
+			}
+			return true
+		}
+		return false
 
 	case *schema.Enum:
+		name := resolver.String()
+		if resolver.Kind() != reflect.String {
+			name = resolver.Interface().(fmt.Stringer).String()
+			if !hasEnumValue(t, name) {
+				qErr := errors.Errorf("invalid value %s for enum %s", name, t.Name)
+				qErr.Path = path.toSlice()
+				r.AddError(qErr)
+				out.WriteString("null")
+				return true
+			}
+		}
 		out.WriteByte('"')
-		out.WriteString(resolver.String())
+		out.WriteString(name)
 		out.WriteByte('"')
+		return false
 
 	default:
 		panic("unreachable")
 	}
 }
 
+// execList resolves the first n elements of the list. It reports whether the list became null
+// because a non-null element resolved to null.
+func (r *Request) execList(ctx context.Context, sels []selected.Selection, elemType common.Type, path *pathSegment, list reflect.Value, n int, out *bytes.Buffer) (null bool) {
+	entryouts := make([]bytes.Buffer, n)
+	entryNulls := make([]bool, n)
+	if selected.HasAsyncSel(sels) {
+		r.sched.Go(n, func(i int) {
+			defer r.handlePanic(ctx)
+			entryNulls[i] = r.execSelectionSet(ctx, sels, elemType, &pathSegment{path, i}, list.Index(i), &entryouts[i])
+		})
+	} else {
+		for i := 0; i < n; i++ {
+			entryNulls[i] = r.execSelectionSet(ctx, sels, elemType, &pathSegment{path, i}, list.Index(i), &entryouts[i])
+		}
+	}
+
+	if _, nonNullElem := elemType.(*common.NonNull); nonNullElem {
+		for _, entryNull := range entryNulls {
+			if entryNull {
+				out.WriteString("null")
+				r.tasks.setNull(path)
+				return true
+			}
+		}
+	}
+
+	out.WriteByte('[')
+	for i := range entryouts {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		out.Write(entryouts[i].Bytes())
+	}
+	out.WriteByte(']')
+	return false
+}
+
+// checkInt32 checks that an integer result is in the range of Int.
+func checkInt32(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		if n := v.Int(); n < math.MinInt32 || n > math.MaxInt32 {
+			return fmt.Errorf("%d is not a 32-bit integer", n)
+		}
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if n := v.Uint(); n > math.MaxInt32 {
+			return fmt.Errorf("%d is not a 32-bit integer", n)
+		}
+	}
+	return nil
+}
+
+func hasEnumValue(t *schema.Enum, name string) bool {
+	for _, v := range t.Values {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func unwrapNonNull(t common.Type) (common.Type, bool) {
 	if nn, ok := t.(*common.NonNull); ok {
 		return nn.OfType, true
@@ -305,6 +488,19 @@ type pathSegment struct {
 	value  interface{}
 }
 
+// within reports whether p is ancestor or one of its descendants. Every path is within the nil path.
+func (p *pathSegment) within(ancestor *pathSegment) bool {
+	if ancestor == nil {
+		return true
+	}
+	for ; p != nil; p = p.parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *pathSegment) toSlice() []interface{} {
 	if p == nil {
 		return nil
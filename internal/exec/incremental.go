@@ -0,0 +1,255 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/exec/resolvable"
+	"github.com/sevlyar/graphql-go/internal/exec/selected"
+	"github.com/sevlyar/graphql-go/internal/query"
+)
+
+// incremental coordinates the payloads deferred with @defer and @stream. Tasks registered while a
+// payload is executed are started once it has been sent.
+type incremental struct {
+	ctx context.Context
+	out chan *Response
+	wg  sync.WaitGroup
+
+	mu      sync.Mutex
+	pending int
+}
+
+// taskGroup holds the tasks registered while a single payload is executed and the paths which
+// became null in it. Tasks below a null path are dropped, their parent is not part of the response.
+type taskGroup struct {
+	mu     sync.Mutex
+	tasks  []*task
+	nulled []*pathSegment
+}
+
+type task struct {
+	path *pathSegment
+	run  func()
+}
+
+// setNull records that the value at path became null. g may be nil if the request is not
+// incremental.
+func (g *taskGroup) setNull(path *pathSegment) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nulled = append(g.nulled, path)
+}
+
+// add registers a task for the value at path. The task has to call send exactly once with last set
+// to true.
+func (inc *incremental) add(g *taskGroup, path *pathSegment, run func()) {
+	inc.mu.Lock()
+	inc.pending++
+	inc.wg.Add(1)
+	inc.mu.Unlock()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = append(g.tasks, &task{path, run})
+}
+
+// release removes the tasks of g and returns those which are still part of the response. inc.mu
+// has to be held.
+func (inc *incremental) release(g *taskGroup) []func() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var runs []func()
+	for _, t := range g.tasks {
+		if g.isNull(t.path) {
+			inc.pending--
+			inc.wg.Done()
+			continue
+		}
+		runs = append(runs, t.run)
+	}
+	g.tasks = nil
+	return runs
+}
+
+func (g *taskGroup) isNull(path *pathSegment) bool {
+	for _, n := range g.nulled {
+		if path.within(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// send delivers a subsequent payload and starts the tasks registered while executing it. The lock
+// is held while sending, so hasNext is consistent with the order in which the payloads arrive.
+func (inc *incremental) send(g *taskGroup, resp *Response, last bool) {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	var runs []func()
+	if g != nil {
+		runs = inc.release(g)
+	}
+	if last {
+		defer inc.wg.Done()
+		inc.pending--
+	}
+	hasNext := inc.pending > 0
+	resp.HasNext = &hasNext
+	select {
+	case inc.out <- resp:
+	case <-inc.ctx.Done():
+	}
+	for _, run := range runs {
+		go run()
+	}
+}
+
+// ExecuteIncremental executes the operation with @defer and @stream enabled. The first Response
+// holds the initial payload, every further Response a deferred fragment or streamed list item.
+// HasNext of the first Response is nil if nothing was deferred.
+func (r *Request) ExecuteIncremental(ctx context.Context, s *resolvable.Schema, op *query.Operation) <-chan *Response {
+	r.Incremental = true
+	r.inc = &incremental{ctx: ctx, out: make(chan *Response)}
+	r.tasks = new(taskGroup)
+
+	go func() {
+		defer close(r.inc.out)
+
+		data, errs := r.Execute(ctx, s, op)
+		initial := &Response{Data: data, Errors: errs}
+		if data == nil {
+			r.tasks.setNull(nil)
+		}
+
+		r.inc.mu.Lock()
+		runs := r.inc.release(r.tasks)
+		hasNext := r.inc.pending > 0
+		r.inc.mu.Unlock()
+		if hasNext {
+			initial.HasNext = &hasNext
+		}
+
+		select {
+		case r.inc.out <- initial:
+		case <-ctx.Done():
+			return
+		}
+		if !hasNext {
+			return
+		}
+
+		for _, run := range runs {
+			go run()
+		}
+		r.inc.wg.Wait()
+	}()
+	return r.inc.out
+}
+
+// fork returns a request sharing the document, limits and incremental delivery of r but with its
+// own errors and tasks.
+func (r *Request) fork() *Request {
+	er := &Request{
+		Request: selected.Request{
+			Schema:      r.Schema,
+			Doc:         r.Doc,
+			Vars:        r.Vars,
+			Incremental: r.Incremental,
+		},
+		Limiter:    r.Limiter,
+		Tracer:     r.Tracer,
+		Logger:     r.Logger,
+		Middleware: r.Middleware,
+		Codecs:     r.Codecs,
+		inc:        r.inc,
+	}
+	if r.inc != nil {
+		er.tasks = new(taskGroup)
+	}
+	return er
+}
+
+func (r *Request) deferFragment(path *pathSegment, d *deferredFragment) {
+	r.inc.add(r.tasks, path, func() {
+		er := r.fork()
+		ctx := er.withScheduler(r.inc.ctx)
+
+		var out bytes.Buffer
+		var null bool
+		func() {
+			defer er.handlePanic(ctx)
+			null = er.execSelections(ctx, d.frag.Sels, path, d.resolver, &out, false)
+		}()
+		if err := ctx.Err(); err != nil {
+			er.Errs = append(er.Errs, errors.Errorf("%s", err))
+		}
+
+		data := out.Bytes()
+		if null {
+			data = nil
+		}
+		r.inc.send(er.tasks, &Response{Data: data, Path: path.toSlice(), Label: d.frag.Label, Errors: er.Errs}, true)
+	})
+}
+
+// execStream resolves the first items of a list field streamed with @stream. The remaining items
+// are delivered one by one in a single task, so they arrive in order. It reports whether the list
+// is null.
+func (r *Request) execStream(ctx context.Context, f *fieldToExec, path *pathSegment, result reflect.Value) (null bool) {
+	t, nonNull := unwrapNonNull(f.field.Type)
+	if !nonNull {
+		if result.IsNil() {
+			f.out.WriteString("null")
+			return true
+		}
+		result = result.Elem()
+	}
+	elemType := t.(*common.List).OfType
+
+	n := result.Len()
+	initial := f.field.Stream.InitialCount
+	if initial > n {
+		initial = n
+	}
+	if r.execList(ctx, f.sels, elemType, path, result, initial, f.out) {
+		return true
+	}
+	if initial == n {
+		return false
+	}
+
+	label := f.field.Stream.Label
+	r.inc.add(r.tasks, path, func() {
+		for i := initial; i < n; i++ {
+			er := r.fork()
+			ctx := er.withScheduler(r.inc.ctx)
+			itemPath := &pathSegment{path, i}
+
+			var out bytes.Buffer
+			var null bool
+			func() {
+				defer er.handlePanic(ctx)
+				null = er.execSelectionSet(ctx, f.sels, elemType, itemPath, result.Index(i), &out)
+			}()
+
+			resp := &Response{Path: itemPath.toSlice(), Label: label, Errors: er.Errs}
+			if _, nonNullElem := elemType.(*common.NonNull); !nonNullElem || !null {
+				resp.Items = append(append([]byte{'['}, out.Bytes()...), ']')
+			}
+			r.inc.send(er.tasks, resp, i == n-1)
+			if ctx.Err() != nil && i < n-1 {
+				r.inc.send(nil, &Response{Errors: []*errors.QueryError{errors.Errorf("%s", ctx.Err())}}, true)
+				return
+			}
+		}
+	})
+	return false
+}
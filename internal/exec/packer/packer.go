@@ -1,6 +1,7 @@
 package packer
 
 import (
+	"encoding"
 	"fmt"
 	"math"
 	"reflect"
@@ -18,8 +19,19 @@ type packer interface {
 type Builder struct {
 	packerMap     map[typePair]*packerMapEntry
 	structPackers []*StructPacker
+	codecs        ScalarCodecs
 }
 
+// ScalarCodec converts the values of a custom scalar, see graphql.ScalarCodec.
+type ScalarCodec struct {
+	ParseValue   func(value interface{}) (interface{}, error)
+	ParseLiteral func(value interface{}, text string) (interface{}, error)
+	Serialize    func(value interface{}) (interface{}, error)
+}
+
+// ScalarCodecs maps the names of custom scalars to their codecs.
+type ScalarCodecs map[string]*ScalarCodec
+
 type typePair struct {
 	graphQLType  common.Type
 	resolverType reflect.Type
@@ -30,9 +42,10 @@ type packerMapEntry struct {
 	targets []*packer
 }
 
-func NewBuilder() *Builder {
+func NewBuilder(codecs ScalarCodecs) *Builder {
 	return &Builder{
 		packerMap: make(map[typePair]*packerMapEntry),
+		codecs:    codecs,
 	}
 }
 
@@ -47,7 +60,7 @@ func (b *Builder) Finish() error {
 		p.defaultStruct = reflect.New(p.structType).Elem()
 		for _, f := range p.fields {
 			if defaultVal := f.field.Default; defaultVal != nil {
-				v, err := f.fieldPacker.Pack(defaultVal.Value(nil))
+				v, err := f.fieldPacker.Pack(Literal(defaultVal, nil))
 				if err != nil {
 					return err
 				}
@@ -77,6 +90,9 @@ func (b *Builder) assignPacker(target *packer, schemaType common.Type, reflectTy
 
 func (b *Builder) makePacker(schemaType common.Type, reflectType reflect.Type) (packer, error) {
 	t, nonNull := unwrapNonNull(schemaType)
+	if reflectType.Kind() == reflect.Struct && reflect.PtrTo(reflectType).Implements(nullableType) {
+		return b.makeOptionalPacker(t, reflectType)
+	}
 	if !nonNull {
 		if reflectType.Kind() != reflect.Ptr {
 			return nil, fmt.Errorf("%s is not a pointer", reflectType)
@@ -102,6 +118,16 @@ func (b *Builder) makePacker(schemaType common.Type, reflectType reflect.Type) (
 }
 
 func (b *Builder) makeNonNullPacker(schemaType common.Type, reflectType reflect.Type) (packer, error) {
+	if t, ok := schemaType.(*schema.Scalar); ok {
+		if codec := b.codecs[t.Name]; codec != nil {
+			return &codecPacker{
+				name:      t.Name,
+				codec:     codec,
+				ValueType: reflectType,
+			}, nil
+		}
+	}
+
 	if u, ok := reflect.New(reflectType).Interface().(Unmarshaler); ok {
 		if !u.ImplementsGraphQLType(schemaType.String()) {
 			return nil, fmt.Errorf("can not unmarshal %s into %s", schemaType, reflectType)
@@ -118,11 +144,13 @@ func (b *Builder) makeNonNullPacker(schemaType common.Type, reflectType reflect.
 		}, nil
 
 	case *schema.Enum:
-		want := reflect.TypeOf("")
-		if reflectType != want {
-			return nil, fmt.Errorf("wrong type, expected %s", want)
+		values, err := EnumValues(t, reflectType)
+		if err != nil {
+			return nil, err
 		}
-		return &ValuePacker{
+		return &enumPacker{
+			name:      t.Name,
+			values:    values,
 			ValueType: reflectType,
 		}, nil
 
@@ -216,15 +244,25 @@ type structPackerField struct {
 }
 
 func (p *StructPacker) Pack(value interface{}) (reflect.Value, error) {
+	value = unwrapVariable(value)
 	if value == nil {
 		return reflect.Value{}, errors.Errorf("got null for non-null")
 	}
 
-	values := value.(map[string]interface{})
+	var values map[string]interface{}
+	if l, ok := value.(literal); ok {
+		obj := l.lit.(*common.ObjectLit)
+		values = make(map[string]interface{}, len(obj.Fields))
+		for _, f := range obj.Fields {
+			values[f.Name.Name] = Literal(f.Value, l.vars)
+		}
+	} else {
+		values = value.(map[string]interface{})
+	}
 	v := reflect.New(p.structType)
 	v.Elem().Set(p.defaultStruct)
 	for _, f := range p.fields {
-		if value, ok := values[f.field.Name.Name]; ok {
+		if value, ok := values[f.field.Name.Name]; ok && !isUnsetVariable(value) {
 			packed, err := f.fieldPacker.Pack(value)
 			if err != nil {
 				return reflect.Value{}, err
@@ -244,8 +282,19 @@ type listPacker struct {
 }
 
 func (e *listPacker) Pack(value interface{}) (reflect.Value, error) {
-	list, ok := value.([]interface{})
-	if !ok {
+	value = unwrapVariable(value)
+	var list []interface{}
+	if l, ok := value.(literal); ok {
+		if listLit, ok := l.lit.(*common.ListLit); ok {
+			list = make([]interface{}, len(listLit.Entries))
+			for i, entry := range listLit.Entries {
+				list[i] = Literal(entry, l.vars)
+			}
+		}
+	} else {
+		list, _ = value.([]interface{})
+	}
+	if list == nil {
 		list = []interface{}{value}
 	}
 
@@ -260,6 +309,54 @@ func (e *listPacker) Pack(value interface{}) (reflect.Value, error) {
 	return v, nil
 }
 
+// Nullable is implemented by input types which record whether a value was given at all. The type has
+// to be a struct with a pointer field Value, which is nil for an explicit null, and a bool field
+// Set, which is false if the argument or input field is absent.
+type Nullable interface {
+	Nullable()
+}
+
+var nullableType = reflect.TypeOf((*Nullable)(nil)).Elem()
+
+func (b *Builder) makeOptionalPacker(schemaType common.Type, reflectType reflect.Type) (packer, error) {
+	valueField, ok := reflectType.FieldByName("Value")
+	if !ok || valueField.Type.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("%s must have a pointer field Value", reflectType)
+	}
+	setField, ok := reflectType.FieldByName("Set")
+	if !ok || setField.Type.Kind() != reflect.Bool {
+		return nil, fmt.Errorf("%s must have a bool field Set", reflectType)
+	}
+	p := &optionalPacker{
+		structType: reflectType,
+		valueIndex: valueField.Index,
+		setIndex:   setField.Index,
+	}
+	// the wrapper accepts null even if the argument is non-null because of its default value
+	if err := b.assignPacker(&p.value, schemaType, valueField.Type); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type optionalPacker struct {
+	structType reflect.Type
+	valueIndex []int
+	setIndex   []int
+	value      packer
+}
+
+func (p *optionalPacker) Pack(value interface{}) (reflect.Value, error) {
+	packed, err := p.value.Pack(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	v := reflect.New(p.structType).Elem()
+	v.FieldByIndex(p.valueIndex).Set(packed)
+	v.FieldByIndex(p.setIndex).SetBool(true)
+	return v, nil
+}
+
 type nullPacker struct {
 	elemPacker packer
 	valueType  reflect.Type
@@ -267,6 +364,7 @@ type nullPacker struct {
 }
 
 func (p *nullPacker) Pack(value interface{}) (reflect.Value, error) {
+	value = unwrapVariable(value)
 	if value == nil {
 		return reflect.Zero(p.valueType), nil
 	}
@@ -290,6 +388,7 @@ type ValuePacker struct {
 }
 
 func (p *ValuePacker) Pack(value interface{}) (reflect.Value, error) {
+	value = plainValue(value)
 	if value == nil {
 		return reflect.Value{}, errors.Errorf("got null for non-null")
 	}
@@ -306,6 +405,7 @@ type unmarshalerPacker struct {
 }
 
 func (p *unmarshalerPacker) Pack(value interface{}) (reflect.Value, error) {
+	value = plainValue(value)
 	if value == nil {
 		return reflect.Value{}, errors.Errorf("got null for non-null")
 	}
@@ -317,6 +417,153 @@ func (p *unmarshalerPacker) Pack(value interface{}) (reflect.Value, error) {
 	return v.Elem(), nil
 }
 
+type codecPacker struct {
+	name      string
+	codec     *ScalarCodec
+	ValueType reflect.Type
+}
+
+func (p *codecPacker) Pack(value interface{}) (reflect.Value, error) {
+	value = unwrapVariable(value)
+	if value == nil {
+		return reflect.Value{}, errors.Errorf("got null for non-null")
+	}
+
+	var parsed interface{}
+	var err error
+	if l, ok := value.(literal); ok && p.codec.ParseLiteral != nil {
+		parsed, err = p.codec.ParseLiteral(l.lit.Value(l.vars), l.lit.String())
+	} else {
+		parsed, err = p.codec.ParseValue(plainValue(value))
+	}
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("could not parse %s: %s", p.name, err)
+	}
+
+	v := reflect.ValueOf(parsed)
+	switch {
+	case !v.IsValid():
+		return reflect.Value{}, fmt.Errorf("codec of %s returned nil", p.name)
+	case v.Type().AssignableTo(p.ValueType):
+		return v, nil
+	case v.Type().ConvertibleTo(p.ValueType):
+		return v.Convert(p.ValueType), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("codec of %s returned %s, which can not be used as %s", p.name, v.Type(), p.ValueType)
+	}
+}
+
+type enumPacker struct {
+	name      string
+	values    map[string]reflect.Value
+	ValueType reflect.Type
+}
+
+func (p *enumPacker) Pack(value interface{}) (reflect.Value, error) {
+	value = plainValue(value)
+	if value == nil {
+		return reflect.Value{}, errors.Errorf("got null for non-null")
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("could not unmarshal %#v (%T) into %s: incompatible type", value, value, p.ValueType)
+	}
+	if p.values == nil {
+		return reflect.ValueOf(s).Convert(p.ValueType), nil
+	}
+	v, ok := p.values[s]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid value %q for enum %s", s, p.name)
+	}
+	return v, nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// EnumValues maps the values of the enum to values of typ. A string type is used with the names of
+// the values as they are, the map is nil then. An integer type has to implement
+// encoding.TextUnmarshaler and fmt.Stringer, which have to agree on each value of the enum.
+func EnumValues(e *schema.Enum, typ reflect.Type) (map[string]reflect.Value, error) {
+	switch typ.Kind() {
+	case reflect.String:
+		return nil, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if reflect.PtrTo(typ).Implements(textUnmarshalerType) && typ.Implements(stringerType) {
+			break
+		}
+		fallthrough
+	default:
+		return nil, fmt.Errorf("can not use %s as enum %s, expected a string type or an integer type implementing encoding.TextUnmarshaler and fmt.Stringer", typ, e.Name)
+	}
+
+	values := make(map[string]reflect.Value, len(e.Values))
+	for _, ev := range e.Values {
+		v := reflect.New(typ)
+		if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(ev.Name)); err != nil {
+			return nil, fmt.Errorf("%s can not unmarshal value %q of enum %s: %s", typ, ev.Name, e.Name, err)
+		}
+		if name := v.Elem().Interface().(fmt.Stringer).String(); name != ev.Name {
+			return nil, fmt.Errorf("%s unmarshals value %q of enum %s into %q", typ, ev.Name, e.Name, name)
+		}
+		values[ev.Name] = v.Elem()
+	}
+	return values, nil
+}
+
+// literal is an input value as written in the query. It is evaluated by the packers, so that the
+// codecs of custom scalars can tell literals from the values of variables.
+type literal struct {
+	lit  common.Literal
+	vars map[string]interface{}
+}
+
+// Literal prepares the literal of an argument for Pack.
+func Literal(lit common.Literal, vars map[string]interface{}) interface{} {
+	return literal{lit, vars}
+}
+
+// unwrapVariable returns the value of a variable and null as they are, other literals stay wrapped.
+func unwrapVariable(value interface{}) interface{} {
+	l, ok := value.(literal)
+	if !ok {
+		return value
+	}
+	switch lit := l.lit.(type) {
+	case *common.Variable:
+		return l.vars[lit.Name]
+	case *common.NullLit:
+		return nil
+	}
+	return value
+}
+
+// isUnsetVariable reports whether the value is a variable without value, which counts as absent.
+func isUnsetVariable(value interface{}) bool {
+	l, ok := value.(literal)
+	if !ok {
+		return false
+	}
+	v, ok := l.lit.(*common.Variable)
+	if !ok {
+		return false
+	}
+	_, ok = l.vars[v.Name]
+	return !ok
+}
+
+// plainValue evaluates a wrapped literal.
+func plainValue(value interface{}) interface{} {
+	if l, ok := value.(literal); ok {
+		return l.lit.Value(l.vars)
+	}
+	return value
+}
+
 type Unmarshaler interface {
 	ImplementsGraphQLType(name string) bool
 	UnmarshalGraphQL(input interface{}) error
@@ -328,33 +575,72 @@ func unmarshalInput(typ reflect.Type, input interface{}) (interface{}, error) {
 	}
 
 	switch typ.Kind() {
-	case reflect.Int32:
-		switch input := input.(type) {
-		case int:
-			if input < math.MinInt32 || input > math.MaxInt32 {
-				return nil, fmt.Errorf("not a 32-bit integer")
-			}
-			return int32(input), nil
-		case float64:
-			coerced := int32(input)
-			if input < math.MinInt32 || input > math.MaxInt32 || float64(coerced) != input {
-				return nil, fmt.Errorf("not a 32-bit integer")
-			}
-			return coerced, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := int32Input(input)
+		if err != nil {
+			return nil, err
+		}
+		v := reflect.New(typ).Elem()
+		if v.OverflowInt(n) {
+			return nil, fmt.Errorf("%d overflows %s", n, typ)
+		}
+		v.SetInt(n)
+		return v.Interface(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := int32Input(input)
+		if err != nil {
+			return nil, err
+		}
+		v := reflect.New(typ).Elem()
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return nil, fmt.Errorf("%d overflows %s", n, typ)
 		}
+		v.SetUint(uint64(n))
+		return v.Interface(), nil
 
-	case reflect.Float64:
+	case reflect.Float32, reflect.Float64:
+		var f float64
 		switch input := input.(type) {
 		case int32:
-			return float64(input), nil
+			f = float64(input)
 		case int:
-			return float64(input), nil
+			f = float64(input)
+		case float64:
+			f = input
+		default:
+			return nil, fmt.Errorf("incompatible type")
+		}
+		v := reflect.New(typ).Elem()
+		if v.OverflowFloat(f) {
+			return nil, fmt.Errorf("%v overflows %s", f, typ)
 		}
+		v.SetFloat(f)
+		return v.Interface(), nil
 	}
 
 	return nil, fmt.Errorf("incompatible type")
 }
 
+// int32Input converts the input of an Int, which is a 32-bit integer in GraphQL.
+func int32Input(input interface{}) (int64, error) {
+	var f float64
+	switch input := input.(type) {
+	case int32:
+		return int64(input), nil
+	case int:
+		f = float64(input)
+	case float64:
+		f = input
+	default:
+		return 0, fmt.Errorf("incompatible type")
+	}
+	if f < math.MinInt32 || f > math.MaxInt32 || f != math.Trunc(f) {
+		return 0, fmt.Errorf("not a 32-bit integer")
+	}
+	return int64(f), nil
+}
+
 func unwrapNonNull(t common.Type) (common.Type, bool) {
 	if nn, ok := t.(*common.NonNull); ok {
 		return nn.OfType, true
@@ -14,16 +14,16 @@ var MetaType *Object
 
 func init() {
 	var err error
-	b := newBuilder(schema.Meta)
+	b := newBuilder(schema.Meta, nil)
 
 	metaSchema := schema.Meta.Types["__Schema"].(*schema.Object)
-	MetaSchema, err = b.makeObjectExec(metaSchema.Name, metaSchema.Fields, nil, false, reflect.TypeOf(&introspection.Schema{}))
+	MetaSchema, err = b.makeObjectExec(metaSchema.Name, metaSchema.Fields, nil, nil, false, reflect.TypeOf(&introspection.Schema{}))
 	if err != nil {
 		panic(err)
 	}
 
 	metaType := schema.Meta.Types["__Type"].(*schema.Object)
-	MetaType, err = b.makeObjectExec(metaType.Name, metaType.Fields, nil, false, reflect.TypeOf(&introspection.Type{}))
+	MetaType, err = b.makeObjectExec(metaType.Name, metaType.Fields, nil, nil, false, reflect.TypeOf(&introspection.Type{}))
 	if err != nil {
 		panic(err)
 	}
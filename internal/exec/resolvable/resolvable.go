@@ -27,6 +27,7 @@ type Object struct {
 	Name           string
 	Fields         map[string]*Field
 	TypeAssertions map[string]*TypeAssertion
+	Interfaces     map[string]bool
 }
 
 type Field struct {
@@ -38,6 +39,7 @@ type Field struct {
 	HasError    bool
 	ValueExec   Resolvable
 	TraceLabel  string
+	Streams     bool // the subscription field's resolver returns a channel
 }
 
 type TypeAssertion struct {
@@ -55,8 +57,8 @@ func (*Object) isResolvable() {}
 func (*List) isResolvable()   {}
 func (*Scalar) isResolvable() {}
 
-func ApplyResolver(s *schema.Schema, resolver interface{}) (*Schema, error) {
-	b := newBuilder(s)
+func ApplyResolver(s *schema.Schema, resolver interface{}, codecs packer.ScalarCodecs) (*Schema, error) {
+	b := newBuilder(s, codecs)
 
 	var query, mutation, subscription Resolvable
 
@@ -95,6 +97,7 @@ type execBuilder struct {
 	schema        *schema.Schema
 	resMap        map[typePair]*resMapEntry
 	packerBuilder *packer.Builder
+	codecs        packer.ScalarCodecs
 }
 
 type typePair struct {
@@ -107,11 +110,12 @@ type resMapEntry struct {
 	targets []*Resolvable
 }
 
-func newBuilder(s *schema.Schema) *execBuilder {
+func newBuilder(s *schema.Schema, codecs packer.ScalarCodecs) *execBuilder {
 	return &execBuilder{
 		schema:        s,
 		resMap:        make(map[typePair]*resMapEntry),
-		packerBuilder: packer.NewBuilder(),
+		packerBuilder: packer.NewBuilder(codecs),
+		codecs:        codecs,
 	}
 }
 
@@ -147,13 +151,13 @@ func (b *execBuilder) makeExec(t common.Type, resolverType reflect.Type) (Resolv
 
 	switch t := t.(type) {
 	case *schema.Object:
-		return b.makeObjectExec(t.Name, t.Fields, nil, nonNull, resolverType)
+		return b.makeObjectExec(t.Name, t.Fields, t.Interfaces, nil, nonNull, resolverType)
 
 	case *schema.Interface:
-		return b.makeObjectExec(t.Name, t.Fields, t.PossibleTypes, nonNull, resolverType)
+		return b.makeObjectExec(t.Name, t.Fields, t.Interfaces, t.PossibleTypes, nonNull, resolverType)
 
 	case *schema.Union:
-		return b.makeObjectExec(t.Name, nil, t.PossibleTypes, nonNull, resolverType)
+		return b.makeObjectExec(t.Name, nil, nil, t.PossibleTypes, nonNull, resolverType)
 	}
 
 	if !nonNull {
@@ -165,9 +169,15 @@ func (b *execBuilder) makeExec(t common.Type, resolverType reflect.Type) (Resolv
 
 	switch t := t.(type) {
 	case *schema.Scalar:
+		if b.codecs[t.Name] != nil {
+			return &Scalar{}, nil // the codec serializes any type
+		}
 		return makeScalarExec(t, resolverType)
 
 	case *schema.Enum:
+		if _, err := packer.EnumValues(t, resolverType); err != nil {
+			return nil, err
+		}
 		return &Scalar{}, nil
 
 	case *common.List:
@@ -188,9 +198,9 @@ func (b *execBuilder) makeExec(t common.Type, resolverType reflect.Type) (Resolv
 func makeScalarExec(t *schema.Scalar, resolverType reflect.Type) (Resolvable, error) {
 	implementsType := false
 	switch r := reflect.New(resolverType).Interface().(type) {
-	case *int32:
+	case *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16, *uint32, *uint64:
 		implementsType = (t.Name == "Int")
-	case *float64:
+	case *float32, *float64:
 		implementsType = (t.Name == "Float")
 	case *string:
 		implementsType = (t.Name == "String")
@@ -205,7 +215,7 @@ func makeScalarExec(t *schema.Scalar, resolverType reflect.Type) (Resolvable, er
 	return &Scalar{}, nil
 }
 
-func (b *execBuilder) makeObjectExec(typeName string, fields schema.FieldList, possibleTypes []*schema.Object, nonNull bool, resolverType reflect.Type) (*Object, error) {
+func (b *execBuilder) makeObjectExec(typeName string, fields schema.FieldList, interfaces []*schema.Interface, possibleTypes []*schema.Object, nonNull bool, resolverType reflect.Type) (*Object, error) {
 	if !nonNull {
 		if resolverType.Kind() != reflect.Ptr && resolverType.Kind() != reflect.Interface {
 			return nil, fmt.Errorf("%s is not a pointer or interface", resolverType)
@@ -251,10 +261,16 @@ func (b *execBuilder) makeObjectExec(typeName string, fields schema.FieldList, p
 		typeAssertions[impl.Name] = a
 	}
 
+	implemented := make(map[string]bool, len(interfaces))
+	for _, intf := range interfaces {
+		implemented[intf.Name] = true
+	}
+
 	return &Object{
 		Name:           typeName,
 		Fields:         Fields,
 		TypeAssertions: typeAssertions,
+		Interfaces:     implemented,
 	}, nil
 }
 
@@ -303,6 +319,18 @@ func (b *execBuilder) makeFieldExec(typeName string, f *schema.Field, m reflect.
 		}
 	}
 
+	out := m.Type.Out(0)
+	streams := false
+	if sub, ok := b.schema.EntryPoints["subscription"]; ok && typeName == sub.TypeName() {
+		if out.Kind() == reflect.Chan {
+			if out.ChanDir()&reflect.RecvDir == 0 {
+				return nil, fmt.Errorf("%s can not be used to receive values", out)
+			}
+			out = out.Elem() // each value sent on the channel resolves the field once
+			streams = true
+		}
+	}
+
 	fe := &Field{
 		Field:       *f,
 		TypeName:    typeName,
@@ -311,8 +339,9 @@ func (b *execBuilder) makeFieldExec(typeName string, f *schema.Field, m reflect.
 		ArgsPacker:  argsPacker,
 		HasError:    hasError,
 		TraceLabel:  fmt.Sprintf("GraphQL field: %s.%s", typeName, f.Name),
+		Streams:     streams,
 	}
-	if err := b.assignExec(&fe.ValueExec, f.Type, m.Type.Out(0)); err != nil {
+	if err := b.assignExec(&fe.ValueExec, f.Type, out); err != nil {
 		return nil, err
 	}
 	return fe, nil
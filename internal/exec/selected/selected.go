@@ -1,8 +1,8 @@
 package selected
 
 import (
-	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/sevlyar/graphql-go/errors"
@@ -20,6 +20,10 @@ type Request struct {
 	Vars   map[string]interface{}
 	Mu     sync.Mutex
 	Errs   []*errors.QueryError
+
+	// Incremental enables @defer and @stream. Otherwise they are ignored and everything is
+	// delivered at once.
+	Incremental bool
 }
 
 func (r *Request) AddError(err *errors.QueryError) {
@@ -53,6 +57,19 @@ type SchemaField struct {
 	Sels        []Selection
 	Async       bool
 	FixedResult reflect.Value
+	Stream      *Stream
+}
+
+// Stream is set on list fields whose items are delivered incrementally.
+type Stream struct {
+	Label        string
+	InitialCount int
+}
+
+// DeferredFragment holds the selections of a fragment which is delivered incrementally.
+type DeferredFragment struct {
+	Label string
+	Sels  []Selection
 }
 
 type TypeAssertion struct {
@@ -65,9 +82,10 @@ type TypenameField struct {
 	Alias string
 }
 
-func (*SchemaField) isSelection()   {}
-func (*TypeAssertion) isSelection() {}
-func (*TypenameField) isSelection() {}
+func (*SchemaField) isSelection()      {}
+func (*TypeAssertion) isSelection()    {}
+func (*TypenameField) isSelection()    {}
+func (*DeferredFragment) isSelection() {}
 
 func applySelectionSet(r *Request, e *resolvable.Object, sels []query.Selection) (flattenedSels []Selection) {
 	for _, sel := range sels {
@@ -122,11 +140,13 @@ func applySelectionSet(r *Request, e *resolvable.Object, sels []query.Selection)
 				var packedArgs reflect.Value
 				if fe.ArgsPacker != nil {
 					args = make(map[string]interface{})
+					literals := make(map[string]interface{})
 					for _, arg := range field.Arguments {
 						args[arg.Name.Name] = arg.Value.Value(r.Vars)
+						literals[arg.Name.Name] = packer.Literal(arg.Value, r.Vars)
 					}
 					var err error
-					packedArgs, err = fe.ArgsPacker.Pack(args)
+					packedArgs, err = fe.ArgsPacker.Pack(literals)
 					if err != nil {
 						r.AddError(errors.Errorf("%s", err))
 						return
@@ -141,6 +161,7 @@ func applySelectionSet(r *Request, e *resolvable.Object, sels []query.Selection)
 					PackedArgs: packedArgs,
 					Sels:       fieldSels,
 					Async:      fe.HasContext || fe.ArgsPacker != nil || fe.HasError || HasAsyncSel(fieldSels),
+					Stream:     streamByDirective(r, fe, field.Directives),
 				})
 			}
 
@@ -149,14 +170,14 @@ func applySelectionSet(r *Request, e *resolvable.Object, sels []query.Selection)
 			if skipByDirective(r, frag.Directives) {
 				continue
 			}
-			flattenedSels = append(flattenedSels, applyFragment(r, e, &frag.Fragment)...)
+			flattenedSels = append(flattenedSels, deferByDirective(r, frag.Directives, applyFragment(r, e, &frag.Fragment))...)
 
 		case *query.FragmentSpread:
 			spread := sel
 			if skipByDirective(r, spread.Directives) {
 				continue
 			}
-			flattenedSels = append(flattenedSels, applyFragment(r, e, &r.Doc.Fragments.Get(spread.Name.Name).Fragment)...)
+			flattenedSels = append(flattenedSels, deferByDirective(r, spread.Directives, applyFragment(r, e, &r.Doc.Fragments.Get(spread.Name.Name).Fragment))...)
 
 		default:
 			panic("invalid type")
@@ -166,18 +187,38 @@ func applySelectionSet(r *Request, e *resolvable.Object, sels []query.Selection)
 }
 
 func applyFragment(r *Request, e *resolvable.Object, frag *query.Fragment) []Selection {
-	if frag.On.Name != "" && frag.On.Name != e.Name {
-		a, ok := e.TypeAssertions[frag.On.Name]
-		if !ok {
-			panic(fmt.Errorf("%q does not implement %q", frag.On, e.Name)) // TODO proper error handling
-		}
+	if frag.On.Name == "" || frag.On.Name == e.Name || e.Interfaces[frag.On.Name] {
+		return applySelectionSet(r, e, frag.Selections)
+	}
 
+	if a, ok := e.TypeAssertions[frag.On.Name]; ok {
 		return []Selection{&TypeAssertion{
 			TypeAssertion: *a,
 			Sels:          applySelectionSet(r, a.TypeExec.(*resolvable.Object), frag.Selections),
 		}}
 	}
-	return applySelectionSet(r, e, frag.Selections)
+
+	// The fragment is on an interface which some of the possible types implement.
+	var sels []Selection
+	for _, name := range sortedTypeAssertions(e) {
+		a := e.TypeAssertions[name]
+		if impl := a.TypeExec.(*resolvable.Object); impl.Interfaces[frag.On.Name] {
+			sels = append(sels, &TypeAssertion{
+				TypeAssertion: *a,
+				Sels:          applySelectionSet(r, impl, frag.Selections),
+			})
+		}
+	}
+	return sels
+}
+
+func sortedTypeAssertions(e *resolvable.Object) []string {
+	names := make([]string, 0, len(e.TypeAssertions))
+	for name := range e.TypeAssertions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func applyField(r *Request, e resolvable.Resolvable, sels []query.Selection) []Selection {
@@ -219,6 +260,74 @@ func skipByDirective(r *Request, directives common.DirectiveList) bool {
 	return false
 }
 
+// deferByDirective wraps the selections of a fragment if it is deferred.
+func deferByDirective(r *Request, directives common.DirectiveList, sels []Selection) []Selection {
+	if !r.Incremental {
+		return sels
+	}
+	d := directives.Get("defer")
+	if d == nil || !directiveIf(r, d) {
+		return sels
+	}
+	return []Selection{&DeferredFragment{
+		Label: directiveLabel(d, r.Vars),
+		Sels:  sels,
+	}}
+}
+
+func streamByDirective(r *Request, fe *resolvable.Field, directives common.DirectiveList) *Stream {
+	if !r.Incremental {
+		return nil
+	}
+	d := directives.Get("stream")
+	if d == nil || !directiveIf(r, d) {
+		return nil
+	}
+	if _, ok := fe.ValueExec.(*resolvable.List); !ok {
+		return nil
+	}
+
+	stream := &Stream{Label: directiveLabel(d, r.Vars)}
+	if lit, ok := d.Args.Get("initialCount"); ok {
+		p := packer.ValuePacker{ValueType: reflect.TypeOf(int32(0))}
+		v, err := p.Pack(lit.Value(r.Vars))
+		if err != nil {
+			r.AddError(errors.Errorf("%s", err))
+			return nil
+		}
+		stream.InitialCount = int(v.Int())
+	}
+	if stream.InitialCount < 0 {
+		r.AddError(errors.Errorf("initialCount of @stream must not be negative"))
+		return nil
+	}
+	return stream
+}
+
+// directiveIf returns the value of the "if" argument of @defer and @stream, which defaults to true.
+func directiveIf(r *Request, d *common.Directive) bool {
+	lit, ok := d.Args.Get("if")
+	if !ok {
+		return true
+	}
+	p := packer.ValuePacker{ValueType: reflect.TypeOf(false)}
+	v, err := p.Pack(lit.Value(r.Vars))
+	if err != nil {
+		r.AddError(errors.Errorf("%s", err))
+		return false
+	}
+	return v.Bool()
+}
+
+func directiveLabel(d *common.Directive, vars map[string]interface{}) string {
+	if lit, ok := d.Args.Get("label"); ok {
+		if label, ok := lit.Value(vars).(string); ok {
+			return label
+		}
+	}
+	return ""
+}
+
 func HasAsyncSel(sels []Selection) bool {
 	for _, sel := range sels {
 		switch sel := sel.(type) {
@@ -230,7 +339,7 @@ func HasAsyncSel(sels []Selection) bool {
 			if HasAsyncSel(sel.Sels) {
 				return true
 			}
-		case *TypenameField:
+		case *TypenameField, *DeferredFragment:
 			// sync
 		default:
 			panic("unreachable")
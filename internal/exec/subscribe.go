@@ -0,0 +1,149 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/exec/resolvable"
+	"github.com/sevlyar/graphql-go/internal/exec/selected"
+	"github.com/sevlyar/graphql-go/internal/query"
+)
+
+// Response is a single result produced by a subscription or a single payload of an incremental
+// delivery. Items, Path, Label and HasNext are only used by the latter.
+type Response struct {
+	Data    json.RawMessage
+	Items   json.RawMessage
+	Path    []interface{}
+	Label   string
+	Errors  []*errors.QueryError
+	HasNext *bool
+}
+
+// Subscribe calls the resolver of the operation's only root field and executes the selection set
+// once for every value received from the returned channel. The returned channel is closed when the
+// resolver closes its channel or when the context gets cancelled.
+func (r *Request) Subscribe(ctx context.Context, s *resolvable.Schema, op *query.Operation) <-chan *Response {
+	var result reflect.Value
+	var f *fieldToExec
+	var err *errors.QueryError
+	func() {
+		defer r.handlePanic(ctx)
+
+		sels := selected.ApplyOperation(&r.Request, s, op)
+		var fields []*fieldToExec
+		collectFieldsToResolve(sels, s.Resolver, &fields, make(map[string]*fieldToExec), nil)
+		if len(fields) != 1 {
+			err = errors.Errorf("subscription must select exactly one top level field, got %d", len(fields))
+			return
+		}
+		f = fields[0]
+
+		if f.field.FixedResult.IsValid() {
+			result = f.field.FixedResult
+			return
+		}
+
+		var resolverErr error
+		if r.Middleware != nil {
+			result, resolverErr = r.callWithMiddleware(ctx, f, &pathSegment{nil, f.field.Alias})
+		} else {
+			result, resolverErr = callResolver(ctx, f)
+		}
+		if resolverErr != nil {
+			err = errors.Errorf("%s", resolverErr)
+			err.Path = []interface{}{f.field.Alias}
+			err.ResolverError = resolverErr
+		}
+	}()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{errors.Errorf("%s", ctxErr)}})
+	}
+	if err != nil {
+		r.AddError(err)
+	}
+	if len(r.Errs) != 0 {
+		return sendAndReturnClosed(&Response{Errors: r.Errs})
+	}
+
+	if result.Kind() != reflect.Chan {
+		// the resolver does not stream, so its value is the one and only event
+		return sendAndReturnClosed(r.execEvent(ctx, f, result))
+	}
+	if result.IsNil() {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{errors.Errorf("resolver of %q returned a nil channel", f.field.Name)}})
+	}
+
+	c := make(chan *Response)
+	go func() {
+		defer close(c)
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: result},
+		}
+		for {
+			chosen, value, ok := reflect.Select(cases)
+			if chosen == 0 || !ok {
+				return // context cancelled or resolver closed its channel
+			}
+
+			select {
+			case c <- r.execEvent(ctx, f, value):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// execEvent resolves the selection set of the subscription field for a single value. Every event
+// gets its own request state, so errors of one event are not reported with the next one.
+func (r *Request) execEvent(ctx context.Context, f *fieldToExec, value reflect.Value) *Response {
+	er := r.fork()
+	ctx = er.withScheduler(ctx)
+
+	var out bytes.Buffer
+	func() {
+		defer er.handlePanic(ctx)
+		var buf bytes.Buffer
+		null := er.execSelectionSet(ctx, f.sels, f.field.Type, &pathSegment{nil, f.field.Alias}, value, &buf)
+
+		if _, nonNull := f.field.Type.(*common.NonNull); nonNull && null {
+			out.WriteString("null")
+			return
+		}
+		out.WriteByte('{')
+		out.WriteByte('"')
+		out.WriteString(f.field.Alias)
+		out.WriteByte('"')
+		out.WriteByte(':')
+		out.Write(buf.Bytes())
+		out.WriteByte('}')
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return &Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}}
+	}
+
+	var data json.RawMessage
+	if out.Len() != 0 {
+		data = out.Bytes()
+	}
+	return &Response{
+		Data:   data,
+		Errors: er.Errs,
+	}
+}
+
+func sendAndReturnClosed(resp *Response) <-chan *Response {
+	c := make(chan *Response, 1)
+	c <- resp
+	close(c)
+	return c
+}
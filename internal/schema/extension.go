@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+)
+
+// extension is an "extend" definition. It is applied after the whole document was parsed, so
+// types can be extended before they are defined.
+type extension struct {
+	typ NamedType // nil if the schema definition is extended
+
+	directives      common.DirectiveList
+	entryPointNames map[string]common.Ident
+	loc             errors.Location
+}
+
+func parseExtension(l *common.Lexer) *extension {
+	ext := &extension{}
+	switch x := l.ConsumeIdent(); x {
+	case "schema":
+		ext.directives = common.ParseDirectives(l)
+		ext.entryPointNames = make(map[string]common.Ident)
+		if l.Peek() == '{' {
+			parseEntryPoints(l, ext.entryPointNames)
+		}
+	case "type":
+		ext.typ = parseObjectDecl(l)
+	case "interface":
+		ext.typ = parseInterfaceDecl(l)
+	case "union":
+		ext.typ = parseUnionDecl(l)
+	case "enum":
+		ext.typ = parseEnumDecl(l)
+	case "input":
+		ext.typ = parseInputDecl(l)
+	case "scalar":
+		ext.typ = parseScalarDecl(l)
+	default:
+		l.SyntaxError(fmt.Sprintf(`unexpected %q, expecting "schema", "type", "enum", "interface", "union", "input" or "scalar"`, x))
+	}
+	return ext
+}
+
+func (s *Schema) applyExtension(ext *extension) *errors.QueryError {
+	if ext.typ == nil {
+		for key, name := range ext.entryPointNames {
+			if prev, ok := s.entryPointNames[key]; ok {
+				return entryPointConflict(key, prev, name)
+			}
+			s.entryPointNames[key] = name
+		}
+		s.SchemaDirectives = append(s.SchemaDirectives, ext.directives...)
+		return nil
+	}
+
+	name := ext.typ.TypeName()
+	t, ok := s.Types[name]
+	if !ok {
+		return extensionError(ext, "cannot extend type %q because it is not defined", name)
+	}
+	if t == Meta.Types[name] {
+		return extensionError(ext, "cannot extend built-in type %q", name)
+	}
+	if t.Kind() != ext.typ.Kind() {
+		return extensionError(ext, "cannot extend %s %q with an extension of kind %s", t.Kind(), name, ext.typ.Kind())
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		t.Directives = append(t.Directives, ext.typ.(*Scalar).Directives...)
+
+	case *Object:
+		x := ext.typ.(*Object)
+		for _, f := range x.Fields {
+			if prev := t.Fields.Get(f.Name); prev != nil {
+				return fieldConflict(name, prev, f)
+			}
+		}
+		t.interfaceNames = append(t.interfaceNames, x.interfaceNames...)
+		t.Fields = append(t.Fields, x.Fields...)
+		t.Directives = append(t.Directives, x.Directives...)
+
+	case *Interface:
+		x := ext.typ.(*Interface)
+		for _, f := range x.Fields {
+			if prev := t.Fields.Get(f.Name); prev != nil {
+				return fieldConflict(name, prev, f)
+			}
+		}
+		t.interfaceNames = append(t.interfaceNames, x.interfaceNames...)
+		t.Fields = append(t.Fields, x.Fields...)
+		t.Directives = append(t.Directives, x.Directives...)
+
+	case *Union:
+		x := ext.typ.(*Union)
+		t.typeNames = append(t.typeNames, x.typeNames...)
+		t.Directives = append(t.Directives, x.Directives...)
+
+	case *Enum:
+		x := ext.typ.(*Enum)
+		for _, v := range x.Values {
+			for _, prev := range t.Values {
+				if prev.Name == v.Name {
+					err := errors.Errorf("value %q of enum %q is already defined", v.Name, name)
+					err.Locations = []errors.Location{v.Loc, prev.Loc}
+					return err
+				}
+			}
+		}
+		t.Values = append(t.Values, x.Values...)
+		t.Directives = append(t.Directives, x.Directives...)
+
+	case *InputObject:
+		x := ext.typ.(*InputObject)
+		for _, v := range x.Values {
+			if prev := t.Values.Get(v.Name.Name); prev != nil {
+				err := errors.Errorf("field %q of input type %q is already defined", v.Name.Name, name)
+				if typeString(prev.Type) != typeString(v.Type) {
+					err.Message += fmt.Sprintf(" with the type %q, conflicting with %q", typeString(prev.Type), typeString(v.Type))
+				}
+				err.Locations = []errors.Location{v.Name.Loc, prev.Name.Loc}
+				return err
+			}
+		}
+		t.Values = append(t.Values, x.Values...)
+		t.Directives = append(t.Directives, x.Directives...)
+	}
+	return nil
+}
+
+func extensionError(ext *extension, format string, a ...interface{}) *errors.QueryError {
+	err := errors.Errorf(format, a...)
+	err.Locations = []errors.Location{ext.loc}
+	return err
+}
+
+// fieldConflict reports a field of an extension which is already defined. The error points to both
+// definitions, which may be in different sources.
+func fieldConflict(typeName string, prev, f *Field) *errors.QueryError {
+	err := errors.Errorf("field %q of type %q is already defined", f.Name, typeName)
+	if typeString(prev.Type) != typeString(f.Type) {
+		err.Message += fmt.Sprintf(" with the type %q, conflicting with %q", typeString(prev.Type), typeString(f.Type))
+	}
+	err.Locations = []errors.Location{f.Loc, prev.Loc}
+	return err
+}
+
+// entryPointConflict reports a root operation type which is already defined. The error points to
+// both definitions, which may be in different sources.
+func entryPointConflict(key string, prev, name common.Ident) *errors.QueryError {
+	err := errors.Errorf("%s operation is already defined", key)
+	if prev.Name != name.Name {
+		err.Message += fmt.Sprintf(" with the type %q, conflicting with %q", prev.Name, name.Name)
+	}
+	err.Locations = []errors.Location{name.Loc, prev.Loc}
+	return err
+}
+
+// typeString formats a type which may not be resolved yet.
+func typeString(t common.Type) string {
+	switch t := t.(type) {
+	case *common.TypeName:
+		return t.Name
+	case *common.List:
+		return "[" + typeString(t.OfType) + "]"
+	case *common.NonNull:
+		return typeString(t.OfType) + "!"
+	default:
+		return t.String()
+	}
+}
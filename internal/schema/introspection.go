@@ -0,0 +1,293 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/sevlyar/graphql-go/errors"
+)
+
+type introspectionSchema struct {
+	QueryType        *introspectionTypeRef `json:"queryType"`
+	MutationType     *introspectionTypeRef `json:"mutationType"`
+	SubscriptionType *introspectionTypeRef `json:"subscriptionType"`
+	Types            []*introspectionType  `json:"types"`
+	Directives       []*struct {
+		Name         string                `json:"name"`
+		Description  *string               `json:"description"`
+		Locations    []string              `json:"locations"`
+		Args         []*introspectionValue `json:"args"`
+		IsRepeatable bool                  `json:"isRepeatable"`
+	} `json:"directives"`
+}
+
+type introspectionType struct {
+	Kind           string  `json:"kind"`
+	Name           string  `json:"name"`
+	Description    *string `json:"description"`
+	SpecifiedByURL *string `json:"specifiedByURL"`
+	Fields         []*struct {
+		Name              string                `json:"name"`
+		Description       *string               `json:"description"`
+		Args              []*introspectionValue `json:"args"`
+		Type              *introspectionTypeRef `json:"type"`
+		IsDeprecated      bool                  `json:"isDeprecated"`
+		DeprecationReason *string               `json:"deprecationReason"`
+	} `json:"fields"`
+	InputFields []*introspectionValue   `json:"inputFields"`
+	Interfaces  []*introspectionTypeRef `json:"interfaces"`
+	EnumValues  []*struct {
+		Name              string  `json:"name"`
+		Description       *string `json:"description"`
+		IsDeprecated      bool    `json:"isDeprecated"`
+		DeprecationReason *string `json:"deprecationReason"`
+	} `json:"enumValues"`
+	PossibleTypes []*introspectionTypeRef `json:"possibleTypes"`
+}
+
+type introspectionValue struct {
+	Name         string                `json:"name"`
+	Description  *string               `json:"description"`
+	Type         *introspectionTypeRef `json:"type"`
+	DefaultValue *string               `json:"defaultValue"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+// ParseIntrospection parses the result of an introspection query, either the object with the
+// "__schema" key or the whole response with a "data" key around it. The schema is rebuilt as a
+// document in the Schema Definition Language, so it is resolved and validated just like Parse does.
+func (s *Schema) ParseIntrospection(data []byte) error {
+	var result struct {
+		Data *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *introspectionSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return errors.Errorf("invalid introspection result: %s", err)
+	}
+	is := result.Schema
+	if is == nil && result.Data != nil {
+		is = result.Data.Schema
+	}
+	if is == nil {
+		return errors.Errorf(`invalid introspection result: missing "__schema"`)
+	}
+
+	w := &sdlWriter{}
+	if err := w.schema(is); err != nil {
+		return err
+	}
+	return s.Parse(w.String())
+}
+
+// sdlWriter turns an introspection result into a schema document.
+type sdlWriter struct {
+	bytes.Buffer
+}
+
+func (w *sdlWriter) schema(is *introspectionSchema) error {
+	w.WriteString("schema {\n")
+	for _, entry := range []struct {
+		key string
+		ref *introspectionTypeRef
+	}{{"query", is.QueryType}, {"mutation", is.MutationType}, {"subscription", is.SubscriptionType}} {
+		if entry.ref != nil {
+			w.WriteString("  " + entry.key + ": " + entry.ref.Name + "\n")
+		}
+	}
+	w.WriteString("}\n")
+
+	for _, d := range is.Directives {
+		if _, ok := Meta.Directives[d.Name]; ok {
+			continue
+		}
+		w.description(d.Description, "")
+		w.WriteString("directive @" + d.Name)
+		if err := w.args(d.Args, ""); err != nil {
+			return err
+		}
+		if d.IsRepeatable {
+			w.WriteString(" repeatable")
+		}
+		w.WriteString(" on " + strings.Join(d.Locations, " | ") + "\n")
+	}
+
+	for _, t := range is.Types {
+		if _, ok := Meta.Types[t.Name]; ok {
+			continue
+		}
+		if err := w.namedType(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *sdlWriter) namedType(t *introspectionType) error {
+	w.description(t.Description, "")
+	switch t.Kind {
+	case "SCALAR":
+		w.WriteString("scalar " + t.Name)
+		if t.SpecifiedByURL != nil {
+			w.WriteString(" @specifiedBy(url: " + printString(*t.SpecifiedByURL) + ")")
+		}
+		w.WriteString("\n")
+
+	case "OBJECT", "INTERFACE":
+		if t.Kind == "OBJECT" {
+			w.WriteString("type " + t.Name)
+		} else {
+			w.WriteString("interface " + t.Name)
+		}
+		for i, intf := range t.Interfaces {
+			if i == 0 {
+				w.WriteString(" implements ")
+			} else {
+				w.WriteString(" & ")
+			}
+			w.WriteString(intf.Name)
+		}
+		w.WriteString(" {\n")
+		for _, f := range t.Fields {
+			w.description(f.Description, "  ")
+			w.WriteString("  " + f.Name)
+			if err := w.args(f.Args, "  "); err != nil {
+				return err
+			}
+			typ, err := typeRef(f.Type)
+			if err != nil {
+				return err
+			}
+			w.WriteString(": " + typ)
+			w.deprecation(f.IsDeprecated, f.DeprecationReason)
+			w.WriteString("\n")
+		}
+		w.WriteString("}\n")
+
+	case "UNION":
+		w.WriteString("union " + t.Name)
+		for i, member := range t.PossibleTypes {
+			if i == 0 {
+				w.WriteString(" = ")
+			} else {
+				w.WriteString(" | ")
+			}
+			w.WriteString(member.Name)
+		}
+		w.WriteString("\n")
+
+	case "ENUM":
+		w.WriteString("enum " + t.Name + " {\n")
+		for _, v := range t.EnumValues {
+			w.description(v.Description, "  ")
+			w.WriteString("  " + v.Name)
+			w.deprecation(v.IsDeprecated, v.DeprecationReason)
+			w.WriteString("\n")
+		}
+		w.WriteString("}\n")
+
+	case "INPUT_OBJECT":
+		w.WriteString("input " + t.Name + " {\n")
+		for _, v := range t.InputFields {
+			if err := w.inputValue(v, "  "); err != nil {
+				return err
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString("}\n")
+
+	default:
+		return errors.Errorf("invalid introspection result: type %q has the unknown kind %q", t.Name, t.Kind)
+	}
+	return nil
+}
+
+// args writes the arguments of a field or directive, one per line if any of them has a
+// description. The indent is the one of the field or directive.
+func (w *sdlWriter) args(args []*introspectionValue, indent string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	multiline := false
+	for _, arg := range args {
+		multiline = multiline || (arg.Description != nil && *arg.Description != "")
+	}
+
+	w.WriteString("(")
+	for i, arg := range args {
+		if multiline {
+			w.WriteString("\n")
+			if err := w.inputValue(arg, indent+"  "); err != nil {
+				return err
+			}
+			continue
+		}
+		if i != 0 {
+			w.WriteString(", ")
+		}
+		if err := w.inputValue(arg, ""); err != nil {
+			return err
+		}
+	}
+	if multiline {
+		w.WriteString("\n" + indent)
+	}
+	w.WriteString(")")
+	return nil
+}
+
+func (w *sdlWriter) inputValue(v *introspectionValue, indent string) error {
+	w.description(v.Description, indent)
+	typ, err := typeRef(v.Type)
+	if err != nil {
+		return err
+	}
+	w.WriteString(indent + v.Name + ": " + typ)
+	if v.DefaultValue != nil {
+		w.WriteString(" = " + *v.DefaultValue)
+	}
+	return nil
+}
+
+func (w *sdlWriter) deprecation(isDeprecated bool, reason *string) {
+	if !isDeprecated {
+		return
+	}
+	w.WriteString(" @deprecated")
+	// the default reason is always reported, it is omitted to keep the original directive
+	if reason != nil && printString(*reason) != Meta.Directives["deprecated"].Args.Get("reason").Default.String() {
+		w.WriteString("(reason: " + printString(*reason) + ")")
+	}
+}
+
+func (w *sdlWriter) description(desc *string, indent string) {
+	if desc != nil && *desc != "" {
+		w.WriteString(indent + printString(*desc) + "\n")
+	}
+}
+
+func typeRef(ref *introspectionTypeRef) (string, error) {
+	if ref == nil {
+		return "", errors.Errorf("invalid introspection result: missing type reference")
+	}
+	switch ref.Kind {
+	case "NON_NULL":
+		t, err := typeRef(ref.OfType)
+		return t + "!", err
+	case "LIST":
+		t, err := typeRef(ref.OfType)
+		return "[" + t + "]", err
+	default:
+		if ref.Name == "" {
+			return "", errors.Errorf("invalid introspection result: type reference of kind %q without a name", ref.Kind)
+		}
+		return ref.Name, nil
+	}
+}
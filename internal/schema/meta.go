@@ -46,6 +46,32 @@ var metaSrc = `
 		reason: String = "No longer supported"
 	) on FIELD_DEFINITION | ENUM_VALUE
 
+	# Exposes a URL that specifies the behavior of this scalar.
+	directive @specifiedBy(
+		# The URL that specifies the behavior of this scalar.
+		url: String!
+	) on SCALAR
+
+	# Directs the executor to deliver this fragment in a subsequent payload, if the request is
+	# executed incrementally.
+	directive @defer(
+		# Deferred when true.
+		if: Boolean! = true
+		# Identifies the payloads of this fragment.
+		label: String
+	) on FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+	# Directs the executor to deliver the items of this list field in subsequent payloads, if the
+	# request is executed incrementally.
+	directive @stream(
+		# Streamed when true.
+		if: Boolean! = true
+		# Identifies the payloads of this field.
+		label: String
+		# The number of items delivered with the initial payload.
+		initialCount: Int = 0
+	) on FIELD
+
 	# A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document.
 	#
 	# In some cases, you need to provide options to alter GraphQL's execution behavior
@@ -57,6 +83,7 @@ var metaSrc = `
 		description: String
 		locations: [__DirectiveLocation!]!
 		args: [__InputValue!]!
+		isRepeatable: Boolean!
 	}
 
 	# A Directive can be adjacent to many parts of the GraphQL language, a
@@ -76,6 +103,8 @@ var metaSrc = `
 		FRAGMENT_SPREAD
 		# Location adjacent to an inline fragment.
 		INLINE_FRAGMENT
+		# Location adjacent to a variable definition.
+		VARIABLE_DEFINITION
 		# Location adjacent to a schema definition.
 		SCHEMA
 		# Location adjacent to a scalar definition.
@@ -160,6 +189,7 @@ var metaSrc = `
 		kind: __TypeKind!
 		name: String
 		description: String
+		specifiedByURL: String
 		fields(includeDeprecated: Boolean = false): [__Field!]
 		interfaces: [__Type!]
 		possibleTypes: [__Type!]
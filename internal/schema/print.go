@@ -0,0 +1,307 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/scanner"
+
+	"github.com/sevlyar/graphql-go/internal/common"
+)
+
+// ToSDL prints the schema in the Schema Definition Language. Directive declarations and types
+// are sorted by name, fields, arguments and values keep the order of their declaration, which is
+// also their order in introspection. Builtin types and directives are omitted.
+func (s *Schema) ToSDL() string {
+	p := &printer{schema: s}
+
+	if len(s.EntryPoints) != 0 || len(s.SchemaDirectives) != 0 {
+		p.description(s.Desc, "")
+		p.WriteString("schema")
+		p.directives(s.SchemaDirectives)
+		p.WriteString(" {\n")
+		for _, key := range []string{"query", "mutation", "subscription"} {
+			if t, ok := s.EntryPoints[key]; ok {
+				fmt.Fprintf(p, "  %s: %s\n", key, t.TypeName())
+			}
+		}
+		p.WriteString("}\n")
+	}
+
+	var directiveNames []string
+	for name, d := range s.Directives {
+		if Meta.Directives[name] != d {
+			directiveNames = append(directiveNames, name)
+		}
+	}
+	sort.Strings(directiveNames)
+	for _, name := range directiveNames {
+		p.separate()
+		p.directiveDecl(s.Directives[name])
+	}
+
+	var typeNames []string
+	for name, t := range s.Types {
+		if Meta.Types[name] != t {
+			typeNames = append(typeNames, name)
+		}
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		p.separate()
+		p.namedType(s.Types[name])
+	}
+
+	return p.String()
+}
+
+type printer struct {
+	bytes.Buffer
+	schema *Schema
+}
+
+// separate starts a new definition with a blank line.
+func (p *printer) separate() {
+	if p.Len() != 0 {
+		p.WriteString("\n")
+	}
+}
+
+func (p *printer) namedType(t NamedType) {
+	p.description(t.Description(), "")
+	switch t := t.(type) {
+	case *Scalar:
+		p.WriteString("scalar " + t.Name)
+		p.directives(t.Directives)
+		p.WriteString("\n")
+
+	case *Object:
+		p.WriteString("type " + t.Name)
+		p.implements(t.Interfaces)
+		p.directives(t.Directives)
+		p.fields(t.Fields)
+
+	case *Interface:
+		p.WriteString("interface " + t.Name)
+		p.implements(t.Interfaces)
+		p.directives(t.Directives)
+		p.fields(t.Fields)
+
+	case *Union:
+		p.WriteString("union " + t.Name)
+		p.directives(t.Directives)
+		for i, member := range t.PossibleTypes {
+			if i == 0 {
+				p.WriteString(" = ")
+			} else {
+				p.WriteString(" | ")
+			}
+			p.WriteString(member.Name)
+		}
+		p.WriteString("\n")
+
+	case *Enum:
+		p.WriteString("enum " + t.Name)
+		p.directives(t.Directives)
+		if len(t.Values) != 0 {
+			p.WriteString(" {\n")
+			for _, v := range t.Values {
+				p.description(v.Desc, "  ")
+				p.WriteString("  " + v.Name)
+				p.directives(v.Directives)
+				p.WriteString("\n")
+			}
+			p.WriteString("}")
+		}
+		p.WriteString("\n")
+
+	case *InputObject:
+		p.WriteString("input " + t.Name)
+		p.directives(t.Directives)
+		if len(t.Values) != 0 {
+			p.WriteString(" {\n")
+			for _, v := range t.Values {
+				p.inputValue(v, "  ")
+				p.WriteString("\n")
+			}
+			p.WriteString("}")
+		}
+		p.WriteString("\n")
+	}
+}
+
+func (p *printer) implements(intfs []*Interface) {
+	for i, intf := range intfs {
+		if i == 0 {
+			p.WriteString(" implements ")
+		} else {
+			p.WriteString(" & ")
+		}
+		p.WriteString(intf.Name)
+	}
+}
+
+func (p *printer) fields(fields FieldList) {
+	if len(fields) != 0 {
+		p.WriteString(" {\n")
+		for _, f := range fields {
+			p.description(f.Desc, "  ")
+			p.WriteString("  " + f.Name)
+			p.args(f.Args, "  ")
+			p.WriteString(": " + f.Type.String())
+			p.directives(f.Directives)
+			p.WriteString("\n")
+		}
+		p.WriteString("}")
+	}
+	p.WriteString("\n")
+}
+
+// args prints arguments on one line, or one per line if any of them has a description.
+func (p *printer) args(args common.InputValueList, indent string) {
+	if len(args) == 0 {
+		return
+	}
+	multiline := false
+	for _, arg := range args {
+		multiline = multiline || arg.Desc != ""
+	}
+
+	p.WriteString("(")
+	for i, arg := range args {
+		if multiline {
+			p.WriteString("\n")
+			p.inputValue(arg, indent+"  ")
+			continue
+		}
+		if i != 0 {
+			p.WriteString(", ")
+		}
+		p.inputValue(arg, "")
+	}
+	if multiline {
+		p.WriteString("\n" + indent)
+	}
+	p.WriteString(")")
+}
+
+func (p *printer) inputValue(v *common.InputValue, indent string) {
+	p.description(v.Desc, indent)
+	p.WriteString(indent + v.Name.Name + ": " + v.Type.String())
+	if v.Default != nil {
+		p.WriteString(" = " + printLiteral(v.Default))
+	}
+	p.directives(v.Directives)
+}
+
+func (p *printer) directiveDecl(d *DirectiveDecl) {
+	p.description(d.Desc, "")
+	p.WriteString("directive @" + d.Name)
+	p.args(d.Args, "")
+	if d.Repeatable {
+		p.WriteString(" repeatable")
+	}
+	p.WriteString(" on " + strings.Join(d.Locs, " | ") + "\n")
+}
+
+// directives prints the directives used on a definition. Arguments which were filled in with the
+// default of their declaration are omitted.
+func (p *printer) directives(directives common.DirectiveList) {
+	for _, d := range directives {
+		p.WriteString(" @" + d.Name.Name)
+		decl := p.schema.Directives[d.Name.Name]
+
+		var args []string
+		for _, arg := range d.Args {
+			if arg.Value == nil {
+				continue
+			}
+			if decl != nil {
+				if argDecl := decl.Args.Get(arg.Name.Name); argDecl != nil && argDecl.Default == arg.Value {
+					continue
+				}
+			}
+			args = append(args, arg.Name.Name+": "+printLiteral(arg.Value))
+		}
+		if len(args) != 0 {
+			p.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+	}
+}
+
+// description prints the description as block string in front of a definition.
+func (p *printer) description(desc string, indent string) {
+	if desc == "" {
+		return
+	}
+	desc = strings.Replace(desc, `"""`, `\"""`, -1)
+	if !strings.Contains(desc, "\n") && !strings.HasSuffix(desc, `"`) {
+		p.WriteString(indent + `"""` + desc + `"""` + "\n")
+		return
+	}
+	p.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(desc, "\n") {
+		if line != "" {
+			p.WriteString(indent + line)
+		}
+		p.WriteString("\n")
+	}
+	p.WriteString(indent + `"""` + "\n")
+}
+
+func printLiteral(lit common.Literal) string {
+	switch lit := lit.(type) {
+	case *common.BasicLit:
+		if lit.Type == scanner.String {
+			return printString(lit.Value(nil).(string))
+		}
+		return lit.Text
+	case *common.ListLit:
+		entries := make([]string, len(lit.Entries))
+		for i, entry := range lit.Entries {
+			entries[i] = printLiteral(entry)
+		}
+		return "[" + strings.Join(entries, ", ") + "]"
+	case *common.ObjectLit:
+		fields := make([]string, len(lit.Fields))
+		for i, f := range lit.Fields {
+			fields[i] = f.Name.Name + ": " + printLiteral(f.Value)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return lit.String()
+	}
+}
+
+// printString quotes the string with the escape sequences of GraphQL.
+func printString(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -14,10 +14,22 @@ type Schema struct {
 	Types       map[string]NamedType
 	Directives  map[string]*DirectiveDecl
 
-	entryPointNames map[string]string
-	objects         []*Object
-	unions          []*Union
-	enums           []*Enum
+	// SchemaDirectives are the directives of the schema definition.
+	SchemaDirectives common.DirectiveList
+	Desc             string
+
+	entryPointNames   map[string]common.Ident
+	schemaDefinitions []*schemaDefinition
+	definitions       []NamedType
+	directiveDecls    []*DirectiveDecl
+	extensions        []*extension
+}
+
+// schemaDefinition is a parsed schema definition. Only the first one is used, the others are
+// recorded to report them with their conflicts.
+type schemaDefinition struct {
+	entryPointNames map[string]common.Ident
+	loc             errors.Location
 }
 
 func (s *Schema) Resolve(name string) common.Type {
@@ -31,50 +43,66 @@ type NamedType interface {
 }
 
 type Scalar struct {
-	Name string
-	Desc string
+	Name       string
+	Directives common.DirectiveList
+	Desc       string
+	Loc        errors.Location
 }
 
 type Object struct {
 	Name       string
 	Interfaces []*Interface
 	Fields     FieldList
+	Directives common.DirectiveList
 	Desc       string
+	Loc        errors.Location
 
-	interfaceNames []string
+	interfaceNames []common.Ident
 }
 
 type Interface struct {
 	Name          string
+	Interfaces    []*Interface
 	PossibleTypes []*Object
 	Fields        FieldList
+	Directives    common.DirectiveList
 	Desc          string
+	Loc           errors.Location
+
+	interfaceNames []common.Ident
 }
 
 type Union struct {
 	Name          string
 	PossibleTypes []*Object
+	Directives    common.DirectiveList
 	Desc          string
+	Loc           errors.Location
 
-	typeNames []string
+	typeNames []common.Ident
 }
 
 type Enum struct {
-	Name   string
-	Values []*EnumValue
-	Desc   string
+	Name       string
+	Values     []*EnumValue
+	Directives common.DirectiveList
+	Desc       string
+	Loc        errors.Location
 }
 
 type EnumValue struct {
 	Name       string
 	Directives common.DirectiveList
 	Desc       string
+	Loc        errors.Location
 }
 
 type InputObject struct {
-	Name   string
-	Desc   string
-	Values common.InputValueList
+	Name       string
+	Desc       string
+	Loc        errors.Location
+	Values     common.InputValueList
+	Directives common.DirectiveList
 }
 
 type FieldList []*Field
@@ -97,10 +125,22 @@ func (l FieldList) Names() []string {
 }
 
 type DirectiveDecl struct {
-	Name string
-	Desc string
-	Locs []string
-	Args common.InputValueList
+	Name       string
+	Desc       string
+	Loc        errors.Location
+	Locs       []string
+	Args       common.InputValueList
+	Repeatable bool
+}
+
+// HasLocation reports whether the directive may be used at the given location.
+func (d *DirectiveDecl) HasLocation(loc string) bool {
+	for _, l := range d.Locs {
+		if l == loc {
+			return true
+		}
+	}
+	return false
 }
 
 func (*Scalar) Kind() string      { return "SCALAR" }
@@ -137,11 +177,12 @@ type Field struct {
 	Type       common.Type
 	Directives common.DirectiveList
 	Desc       string
+	Loc        errors.Location
 }
 
 func New() *Schema {
 	s := &Schema{
-		entryPointNames: make(map[string]string),
+		entryPointNames: make(map[string]common.Ident),
 		Types:           make(map[string]NamedType),
 		Directives:      make(map[string]*DirectiveDecl),
 	}
@@ -154,245 +195,215 @@ func New() *Schema {
 	return s
 }
 
-func (s *Schema) Parse(schemaString string) error {
-	sc := &scanner.Scanner{
-		Mode: scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanStrings,
-	}
-	sc.Init(strings.NewReader(schemaString))
-
-	l := common.New(sc)
-	err := l.CatchSyntaxError(func() {
-		parseSchema(s, l)
-	})
-	if err != nil {
-		return err
-	}
-
-	for _, t := range s.Types {
-		if err := resolveNamedType(s, t); err != nil {
-			return err
-		}
-	}
-	for _, d := range s.Directives {
-		for _, arg := range d.Args {
-			t, err := common.ResolveType(arg.Type, s.Resolve)
-			if err != nil {
-				return err
-			}
-			arg.Type = t
-		}
-	}
-
-	s.EntryPoints = make(map[string]NamedType)
-	for key, name := range s.entryPointNames {
-		t, ok := s.Types[name]
-		if !ok {
-			if !ok {
-				return errors.Errorf("type %q not found", name)
-			}
-		}
-		s.EntryPoints[key] = t
-	}
-
-	for _, obj := range s.objects {
-		obj.Interfaces = make([]*Interface, len(obj.interfaceNames))
-		for i, intfName := range obj.interfaceNames {
-			t, ok := s.Types[intfName]
-			if !ok {
-				return errors.Errorf("interface %q not found", intfName)
-			}
-			intf, ok := t.(*Interface)
-			if !ok {
-				return errors.Errorf("type %q is not an interface", intfName)
-			}
-			obj.Interfaces[i] = intf
-			intf.PossibleTypes = append(intf.PossibleTypes, obj)
-		}
-	}
-
-	for _, union := range s.unions {
-		union.PossibleTypes = make([]*Object, len(union.typeNames))
-		for i, name := range union.typeNames {
-			t, ok := s.Types[name]
-			if !ok {
-				return errors.Errorf("object type %q not found", name)
-			}
-			obj, ok := t.(*Object)
-			if !ok {
-				return errors.Errorf("type %q is not an object", name)
-			}
-			union.PossibleTypes[i] = obj
-		}
-	}
-
-	for _, enum := range s.enums {
-		for _, value := range enum.Values {
-			if err := resolveDirectives(s, value.Directives); err != nil {
-				return err
-			}
-		}
-	}
+// Source is a named schema document.
+type Source struct {
+	// Name identifies the source in the locations of errors, e.g. a file name.
+	Name string
+	Body string
+}
 
-	return nil
+// Parse parses the schema document, resolves all references and validates the schema. Except for
+// syntax errors, all problems are reported at once as errors.SchemaErrors.
+func (s *Schema) Parse(schemaString string) error {
+	return s.ParseSources(Source{Body: schemaString})
 }
 
-func resolveNamedType(s *Schema, t NamedType) error {
-	switch t := t.(type) {
-	case *Object:
-		for _, f := range t.Fields {
-			if err := resolveField(s, f); err != nil {
-				return err
-			}
+// ParseSources parses several documents as parts of one schema. Each document may extend the
+// types of the others. The sources are resolved and validated together like in Parse.
+func (s *Schema) ParseSources(sources ...Source) error {
+	for _, src := range sources {
+		sc := &scanner.Scanner{
+			Mode: scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanStrings,
 		}
-	case *Interface:
-		for _, f := range t.Fields {
-			if err := resolveField(s, f); err != nil {
-				return err
-			}
-		}
-	case *InputObject:
-		if err := resolveInputObject(s, t.Values); err != nil {
+		sc.Init(strings.NewReader(src.Body))
+		sc.Filename = src.Name
+
+		l := common.New(sc)
+		err := l.CatchSyntaxError(func() {
+			parseSchema(s, l)
+		})
+		if err != nil {
 			return err
 		}
 	}
-	return nil
-}
 
-func resolveField(s *Schema, f *Field) error {
-	t, err := common.ResolveType(f.Type, s.Resolve)
-	if err != nil {
-		return err
-	}
-	f.Type = t
-	if err := resolveDirectives(s, f.Directives); err != nil {
-		return err
+	v := &validator{schema: s}
+	v.resolve()
+	if !v.unresolved {
+		v.validate()
 	}
-	return resolveInputObject(s, f.Args)
+	return v.err()
 }
 
-func resolveDirectives(s *Schema, directives common.DirectiveList) error {
-	for _, d := range directives {
-		dirName := d.Name.Name
-		dd, ok := s.Directives[dirName]
-		if !ok {
-			return errors.Errorf("directive %q not found", dirName)
-		}
-		for _, arg := range d.Args {
-			if dd.Args.Get(arg.Name.Name) == nil {
-				return errors.Errorf("invalid argument %q for directive %q", arg.Name.Name, dirName)
-			}
-		}
-		for _, arg := range dd.Args {
-			if _, ok := d.Args.Get(arg.Name.Name); !ok {
-				d.Args = append(d.Args, common.Argument{Name: arg.Name, Value: arg.Default})
-			}
-		}
+// addType registers a parsed type definition. A duplicate definition does not replace the first
+// one, it is reported by the validation.
+func (s *Schema) addType(t NamedType) {
+	if _, ok := s.Types[t.TypeName()]; !ok {
+		s.Types[t.TypeName()] = t
 	}
-	return nil
+	s.definitions = append(s.definitions, t)
 }
 
-func resolveInputObject(s *Schema, values common.InputValueList) error {
-	for _, v := range values {
-		t, err := common.ResolveType(v.Type, s.Resolve)
-		if err != nil {
-			return err
-		}
-		v.Type = t
-	}
-	return nil
-}
+// definitionKeywords start the definitions of a schema document.
+var definitionKeywords = []string{"schema", "type", "interface", "union", "enum", "input", "scalar", "directive", "extend"}
 
 func parseSchema(s *Schema, l *common.Lexer) {
 	for l.Peek() != scanner.EOF {
-		desc := l.DescComment()
+		if l.PeekKeyword("extend") {
+			loc := l.Location()
+			l.Consume()
+			ext := parseExtension(l)
+			ext.loc = loc
+			s.extensions = append(s.extensions, ext)
+			continue
+		}
+
+		desc := l.Description()
+		loc := l.Location()
 		switch x := l.ConsumeIdent(); x {
 		case "schema":
-			l.ConsumeToken('{')
-			for l.Peek() != '}' {
-				name := l.ConsumeIdent()
-				l.ConsumeToken(':')
-				typ := l.ConsumeIdent()
-				s.entryPointNames[name] = typ
+			// only the first schema definition is used, others are reported by the validation
+			directives := common.ParseDirectives(l)
+			entryPointNames := make(map[string]common.Ident)
+			parseEntryPoints(l, entryPointNames)
+			s.schemaDefinitions = append(s.schemaDefinitions, &schemaDefinition{entryPointNames, loc})
+			if len(s.schemaDefinitions) == 1 {
+				s.Desc = desc
+				s.SchemaDirectives = directives
+				for key, name := range entryPointNames {
+					s.entryPointNames[key] = name
+				}
 			}
-			l.ConsumeToken('}')
 		case "type":
 			obj := parseObjectDecl(l)
 			obj.Desc = desc
-			s.Types[obj.Name] = obj
-			s.objects = append(s.objects, obj)
+			s.addType(obj)
 		case "interface":
 			intf := parseInterfaceDecl(l)
 			intf.Desc = desc
-			s.Types[intf.Name] = intf
+			s.addType(intf)
 		case "union":
 			union := parseUnionDecl(l)
 			union.Desc = desc
-			s.Types[union.Name] = union
-			s.unions = append(s.unions, union)
+			s.addType(union)
 		case "enum":
 			enum := parseEnumDecl(l)
 			enum.Desc = desc
-			s.Types[enum.Name] = enum
-			s.enums = append(s.enums, enum)
+			s.addType(enum)
 		case "input":
 			input := parseInputDecl(l)
 			input.Desc = desc
-			s.Types[input.Name] = input
+			s.addType(input)
 		case "scalar":
-			name := l.ConsumeIdent()
-			s.Types[name] = &Scalar{Name: name, Desc: desc}
+			scalar := parseScalarDecl(l)
+			scalar.Desc = desc
+			s.addType(scalar)
 		case "directive":
 			directive := parseDirectiveDecl(l)
 			directive.Desc = desc
-			s.Directives[directive.Name] = directive
+			if _, ok := s.Directives[directive.Name]; !ok {
+				s.Directives[directive.Name] = directive
+			}
+			s.directiveDecls = append(s.directiveDecls, directive)
 		default:
-			l.SyntaxError(fmt.Sprintf(`unexpected %q, expecting "schema", "type", "enum", "interface", "union", "input", "scalar" or "directive"`, x))
+			l.SyntaxError(fmt.Sprintf(`unexpected %q, expecting "schema", "type", "enum", "interface", "union", "input", "scalar", "directive" or "extend"`, x))
 		}
 	}
 }
 
+func parseEntryPoints(l *common.Lexer, entryPointNames map[string]common.Ident) {
+	l.ConsumeToken('{')
+	for l.Peek() != '}' {
+		name := l.ConsumeIdent()
+		l.ConsumeToken(':')
+		entryPointNames[name] = l.ConsumeIdentWithLoc()
+	}
+	l.ConsumeToken('}')
+}
+
 func parseObjectDecl(l *common.Lexer) *Object {
 	o := &Object{}
+	o.Loc = l.Location()
 	o.Name = l.ConsumeIdent()
-	if l.Peek() == scanner.Ident {
-		l.ConsumeKeyword("implements")
-		for {
-			o.interfaceNames = append(o.interfaceNames, l.ConsumeIdent())
-			if l.Peek() == '{' {
-				break
-			}
-		}
+	if l.PeekKeyword("implements") {
+		l.Consume()
+		o.interfaceNames = parseImplementsInterfaces(l)
 	}
-	l.ConsumeToken('{')
+	o.Directives = common.ParseDirectives(l)
 	o.Fields = parseFields(l)
-	l.ConsumeToken('}')
 	return o
 }
 
+// parseImplementsInterfaces parses the interfaces of an object or interface. Besides separating
+// them by "&", the legacy form separating them by spaces or commas is accepted.
+func parseImplementsInterfaces(l *common.Lexer) []common.Ident {
+	if l.Peek() == '&' {
+		l.ConsumeToken('&')
+	}
+	names := []common.Ident{l.ConsumeIdentWithLoc()}
+	for {
+		switch {
+		case l.Peek() == '&':
+			l.ConsumeToken('&')
+			names = append(names, l.ConsumeIdentWithLoc())
+		case l.Peek() == scanner.Ident && !peekDefinitionKeyword(l):
+			names = append(names, l.ConsumeIdentWithLoc())
+		default:
+			return names
+		}
+	}
+}
+
+func peekDefinitionKeyword(l *common.Lexer) bool {
+	for _, keyword := range definitionKeywords {
+		if l.PeekKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 func parseInterfaceDecl(l *common.Lexer) *Interface {
 	i := &Interface{}
+	i.Loc = l.Location()
 	i.Name = l.ConsumeIdent()
-	l.ConsumeToken('{')
+	if l.PeekKeyword("implements") {
+		l.Consume()
+		i.interfaceNames = parseImplementsInterfaces(l)
+	}
+	i.Directives = common.ParseDirectives(l)
 	i.Fields = parseFields(l)
-	l.ConsumeToken('}')
 	return i
 }
 
 func parseUnionDecl(l *common.Lexer) *Union {
 	union := &Union{}
+	union.Loc = l.Location()
 	union.Name = l.ConsumeIdent()
+	union.Directives = common.ParseDirectives(l)
+	if l.Peek() != '=' {
+		return union
+	}
 	l.ConsumeToken('=')
-	union.typeNames = []string{l.ConsumeIdent()}
+	if l.Peek() == '|' {
+		l.ConsumeToken('|')
+	}
+	union.typeNames = []common.Ident{l.ConsumeIdentWithLoc()}
 	for l.Peek() == '|' {
 		l.ConsumeToken('|')
-		union.typeNames = append(union.typeNames, l.ConsumeIdent())
+		union.typeNames = append(union.typeNames, l.ConsumeIdentWithLoc())
 	}
 	return union
 }
 
 func parseInputDecl(l *common.Lexer) *InputObject {
 	i := &InputObject{}
+	i.Loc = l.Location()
 	i.Name = l.ConsumeIdent()
+	i.Directives = common.ParseDirectives(l)
+	if l.Peek() != '{' {
+		return i
+	}
 	l.ConsumeToken('{')
 	for l.Peek() != '}' {
 		i.Values = append(i.Values, common.ParseInputValue(l))
@@ -403,11 +414,17 @@ func parseInputDecl(l *common.Lexer) *InputObject {
 
 func parseEnumDecl(l *common.Lexer) *Enum {
 	enum := &Enum{}
+	enum.Loc = l.Location()
 	enum.Name = l.ConsumeIdent()
+	enum.Directives = common.ParseDirectives(l)
+	if l.Peek() != '{' {
+		return enum
+	}
 	l.ConsumeToken('{')
 	for l.Peek() != '}' {
 		v := &EnumValue{}
-		v.Desc = l.DescComment()
+		v.Desc = l.Description()
+		v.Loc = l.Location()
 		v.Name = l.ConsumeIdent()
 		v.Directives = common.ParseDirectives(l)
 		enum.Values = append(enum.Values, v)
@@ -416,8 +433,17 @@ func parseEnumDecl(l *common.Lexer) *Enum {
 	return enum
 }
 
+func parseScalarDecl(l *common.Lexer) *Scalar {
+	scalar := &Scalar{}
+	scalar.Loc = l.Location()
+	scalar.Name = l.ConsumeIdent()
+	scalar.Directives = common.ParseDirectives(l)
+	return scalar
+}
+
 func parseDirectiveDecl(l *common.Lexer) *DirectiveDecl {
 	d := &DirectiveDecl{}
+	d.Loc = l.Location()
 	l.ConsumeToken('@')
 	d.Name = l.ConsumeIdent()
 	if l.Peek() == '(' {
@@ -428,7 +454,14 @@ func parseDirectiveDecl(l *common.Lexer) *DirectiveDecl {
 		}
 		l.ConsumeToken(')')
 	}
+	if l.PeekKeyword("repeatable") {
+		l.Consume()
+		d.Repeatable = true
+	}
 	l.ConsumeKeyword("on")
+	if l.Peek() == '|' {
+		l.ConsumeToken('|')
+	}
 	for {
 		loc := l.ConsumeIdent()
 		d.Locs = append(d.Locs, loc)
@@ -440,11 +473,17 @@ func parseDirectiveDecl(l *common.Lexer) *DirectiveDecl {
 	return d
 }
 
+// parseFields parses the optional fields definition of an object or interface.
 func parseFields(l *common.Lexer) FieldList {
+	if l.Peek() != '{' {
+		return nil
+	}
+	l.ConsumeToken('{')
 	var fields FieldList
 	for l.Peek() != '}' {
 		f := &Field{}
-		f.Desc = l.DescComment()
+		f.Desc = l.Description()
+		f.Loc = l.Location()
 		f.Name = l.ConsumeIdent()
 		if l.Peek() == '(' {
 			l.ConsumeToken('(')
@@ -458,5 +497,6 @@ func parseFields(l *common.Lexer) FieldList {
 		f.Directives = common.ParseDirectives(l)
 		fields = append(fields, f)
 	}
+	l.ConsumeToken('}')
 	return fields
 }
@@ -0,0 +1,663 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+)
+
+// validator resolves the references of a parsed schema and checks it against the rules of the
+// type system. It collects all problems instead of stopping at the first one.
+type validator struct {
+	schema *Schema
+	errs   errors.SchemaErrors
+
+	// unresolved is set if a type reference could not be resolved. Such a schema can not be
+	// validated.
+	unresolved bool
+}
+
+func (v *validator) addErr(loc errors.Location, format string, a ...interface{}) {
+	err := errors.Errorf(format, a...)
+	err.Locations = []errors.Location{loc}
+	v.errs = append(v.errs, err)
+}
+
+// addConflict reports a problem with two definitions, the location of the previous one follows the
+// location of the problem.
+func (v *validator) addConflict(loc, prev errors.Location, format string, a ...interface{}) {
+	err := errors.Errorf(format, a...)
+	err.Locations = []errors.Location{loc, prev}
+	v.errs = append(v.errs, err)
+}
+
+// err returns the problems ordered by their location, or nil.
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i].Locations, v.errs[j].Locations
+		return len(a) != 0 && len(b) != 0 && a[0].Before(b[0])
+	})
+	return v.errs
+}
+
+// isDefinition reports whether t is the definition registered for its name and not a duplicate.
+func (v *validator) isDefinition(t NamedType) bool {
+	return v.schema.Types[t.TypeName()] == t
+}
+
+// resolve replaces type names by the types they refer to.
+func (v *validator) resolve() {
+	s := v.schema
+	for _, ext := range s.extensions {
+		if err := s.applyExtension(ext); err != nil {
+			v.errs = append(v.errs, err)
+		}
+	}
+	s.extensions = nil
+
+	for _, t := range s.definitions {
+		if !v.isDefinition(t) {
+			continue
+		}
+		switch t := t.(type) {
+		case *Object:
+			v.resolveFields(t.Fields)
+			t.Interfaces = v.resolveInterfaces(t.interfaceNames)
+		case *Interface:
+			v.resolveFields(t.Fields)
+			t.Interfaces = v.resolveInterfaces(t.interfaceNames)
+		case *Union:
+			t.PossibleTypes = v.resolveObjects(t.typeNames)
+		case *InputObject:
+			v.resolveInputValues(t.Values)
+		}
+	}
+	for _, t := range s.definitions {
+		if obj, ok := t.(*Object); ok && v.isDefinition(obj) {
+			for _, intf := range obj.Interfaces {
+				intf.PossibleTypes = append(intf.PossibleTypes, obj)
+			}
+		}
+	}
+
+	for _, d := range s.directiveDecls {
+		if s.Directives[d.Name] == d {
+			v.resolveInputValues(d.Args)
+		}
+	}
+
+	s.EntryPoints = make(map[string]NamedType)
+	for key, name := range s.entryPointNames {
+		t, ok := s.Types[name.Name]
+		if !ok {
+			v.addErr(name.Loc, "type %q not found", name.Name)
+			continue
+		}
+		s.EntryPoints[key] = t
+	}
+}
+
+func (v *validator) resolveFields(fields FieldList) {
+	for _, f := range fields {
+		t, err := common.ResolveType(f.Type, v.schema.Resolve)
+		if err != nil {
+			v.errs = append(v.errs, err)
+			v.unresolved = true
+		} else {
+			f.Type = t
+		}
+		v.resolveInputValues(f.Args)
+	}
+}
+
+func (v *validator) resolveInputValues(values common.InputValueList) {
+	for _, iv := range values {
+		t, err := common.ResolveType(iv.Type, v.schema.Resolve)
+		if err != nil {
+			v.errs = append(v.errs, err)
+			v.unresolved = true
+			continue
+		}
+		iv.Type = t
+	}
+}
+
+func (v *validator) resolveInterfaces(names []common.Ident) []*Interface {
+	var intfs []*Interface
+	for _, name := range names {
+		t, ok := v.schema.Types[name.Name]
+		if !ok {
+			v.addErr(name.Loc, "interface %q not found", name.Name)
+			continue
+		}
+		intf, ok := t.(*Interface)
+		if !ok {
+			v.addErr(name.Loc, "type %q is not an interface", name.Name)
+			continue
+		}
+		if implements(intfs, intf) {
+			v.addErr(name.Loc, "interface %q is implemented more than once", name.Name)
+			continue
+		}
+		intfs = append(intfs, intf)
+	}
+	return intfs
+}
+
+func (v *validator) resolveObjects(names []common.Ident) []*Object {
+	var objs []*Object
+	for _, name := range names {
+		t, ok := v.schema.Types[name.Name]
+		if !ok {
+			v.addErr(name.Loc, "object type %q not found", name.Name)
+			continue
+		}
+		obj, ok := t.(*Object)
+		if !ok {
+			v.addErr(name.Loc, "type %q is not an object", name.Name)
+			continue
+		}
+		for _, prev := range objs {
+			if prev == obj {
+				v.addErr(name.Loc, "type %q is a member of the union more than once", name.Name)
+			}
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// validate checks the resolved schema. Types without fields or values are accepted for
+// compatibility with earlier versions.
+func (v *validator) validate() {
+	s := v.schema
+	for _, t := range s.definitions {
+		loc := typeLocation(t)
+		if !v.isDefinition(t) {
+			if prev := s.Types[t.TypeName()]; Meta.Types[t.TypeName()] != prev {
+				v.addConflict(loc, typeLocation(prev), "type %q is defined more than once", t.TypeName())
+			} else {
+				v.addErr(loc, "type %q is defined more than once", t.TypeName())
+			}
+			continue
+		}
+		v.validateName(loc, t.TypeName())
+
+		switch t := t.(type) {
+		case *Scalar:
+			v.validateDirectives(t.Directives, "SCALAR")
+
+		case *Object:
+			v.validateFields(t, t.Fields)
+			v.validateImplementations(t, t.Fields, t.Interfaces)
+			v.validateDirectives(t.Directives, "OBJECT")
+
+		case *Interface:
+			v.validateFields(t, t.Fields)
+			v.validateImplementations(t, t.Fields, t.Interfaces)
+			v.validateDirectives(t.Directives, "INTERFACE")
+
+		case *Union:
+			v.validateDirectives(t.Directives, "UNION")
+
+		case *Enum:
+			for i, value := range t.Values {
+				for _, prev := range t.Values[:i] {
+					if prev.Name == value.Name {
+						v.addErr(value.Loc, "value %q of enum %q is defined more than once", value.Name, t.Name)
+					}
+				}
+				switch value.Name {
+				case "true", "false", "null":
+					v.addErr(value.Loc, "enum %q can not have the value %q", t.Name, value.Name)
+				}
+				v.validateName(value.Loc, value.Name)
+				v.validateDirectives(value.Directives, "ENUM_VALUE")
+			}
+			v.validateDirectives(t.Directives, "ENUM")
+
+		case *InputObject:
+			v.validateInputValues(t.Values, "INPUT_FIELD_DEFINITION", fmt.Sprintf("field %%q of input type %q", t.Name))
+			v.validateDirectives(t.Directives, "INPUT_OBJECT")
+		}
+	}
+	v.validateInputCycles()
+
+	for _, d := range s.directiveDecls {
+		if prev := s.Directives[d.Name]; prev != d {
+			if Meta.Directives[d.Name] != prev {
+				v.addConflict(d.Loc, prev.Loc, "directive %q is defined more than once", d.Name)
+			} else {
+				v.addErr(d.Loc, "directive %q is defined more than once", d.Name)
+			}
+			continue
+		}
+		v.validateName(d.Loc, d.Name)
+		v.validateInputValues(d.Args, "ARGUMENT_DEFINITION", fmt.Sprintf("argument %%q of directive %q", "@"+d.Name))
+		for _, loc := range d.Locs {
+			if !isDirectiveLocation(s, loc) {
+				v.addErr(d.Loc, "directive %q has the unknown location %q", d.Name, loc)
+			}
+		}
+	}
+
+	for i, def := range s.schemaDefinitions {
+		if i == 0 {
+			continue
+		}
+		first := s.schemaDefinitions[0]
+		v.addConflict(def.loc, first.loc, "schema is defined more than once")
+		for key, name := range def.entryPointNames {
+			if prev, ok := first.entryPointNames[key]; ok {
+				v.errs = append(v.errs, entryPointConflict(key, prev, name))
+			}
+		}
+	}
+	v.validateDirectives(s.SchemaDirectives, "SCHEMA")
+	for key, name := range s.entryPointNames {
+		switch key {
+		case "query", "mutation", "subscription":
+		default:
+			v.addErr(name.Loc, "unknown operation type %q", key)
+			continue
+		}
+		if _, ok := s.EntryPoints[key].(*Object); !ok {
+			v.addErr(name.Loc, "root %s type %q must be an object type", key, name.Name)
+		}
+	}
+}
+
+func typeLocation(t NamedType) errors.Location {
+	switch t := t.(type) {
+	case *Scalar:
+		return t.Loc
+	case *Object:
+		return t.Loc
+	case *Interface:
+		return t.Loc
+	case *Union:
+		return t.Loc
+	case *Enum:
+		return t.Loc
+	case *InputObject:
+		return t.Loc
+	default:
+		panic("unreachable")
+	}
+}
+
+// validateName rejects names reserved for introspection, except in the definitions of Meta.
+func (v *validator) validateName(loc errors.Location, name string) {
+	if v.schema != Meta && strings.HasPrefix(name, "__") {
+		v.addErr(loc, "name %q must not begin with \"__\", which is reserved by GraphQL introspection", name)
+	}
+}
+
+func isDirectiveLocation(s *Schema, loc string) bool {
+	locs, ok := s.Types["__DirectiveLocation"].(*Enum)
+	if !ok {
+		return false
+	}
+	for _, value := range locs.Values {
+		if value.Name == loc {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) validateFields(t NamedType, fields FieldList) {
+	for i, f := range fields {
+		if fields[:i].Get(f.Name) != nil {
+			v.addErr(f.Loc, "field %q of type %q is defined more than once", f.Name, t.TypeName())
+		}
+		v.validateName(f.Loc, f.Name)
+		if !isOutputType(f.Type) {
+			v.addErr(f.Loc, "field %q of type %q has the input type %q, which can not be used as output", f.Name, t.TypeName(), f.Type)
+		}
+		v.validateInputValues(f.Args, "ARGUMENT_DEFINITION", fmt.Sprintf("argument %%q of field %q", t.TypeName()+"."+f.Name))
+		v.validateDirectives(f.Directives, "FIELD_DEFINITION")
+	}
+}
+
+// validateInputValues checks arguments or input fields. The description has to contain a %q verb
+// for the name of the value.
+func (v *validator) validateInputValues(values common.InputValueList, directiveLoc string, desc string) {
+	for i, iv := range values {
+		name := fmt.Sprintf(desc, iv.Name.Name)
+		for _, prev := range values[:i] {
+			if prev.Name.Name == iv.Name.Name {
+				v.addErr(iv.Loc, "%s is defined more than once", name)
+			}
+		}
+		v.validateName(iv.Loc, iv.Name.Name)
+		if !isInputType(iv.Type) {
+			v.addErr(iv.TypeLoc, "%s has the output type %q, which can not be used as input", name, iv.Type)
+		} else if iv.Default != nil {
+			if ok, reason := validateValue(iv.Default, iv.Type); !ok {
+				v.addErr(iv.Default.Location(), "invalid default value %s for %s: %s", iv.Default, name, reason)
+			}
+		}
+		v.validateDirectives(iv.Directives, directiveLoc)
+	}
+}
+
+// validateDirectives checks the directives used at the given location and fills in the defaults
+// of missing arguments.
+func (v *validator) validateDirectives(directives common.DirectiveList, loc string) {
+	for i, d := range directives {
+		name := d.Name.Name
+		dd, ok := v.schema.Directives[name]
+		if !ok {
+			v.addErr(d.Name.Loc, "directive %q not found", name)
+			continue
+		}
+		if !dd.HasLocation(loc) {
+			v.addErr(d.Name.Loc, "directive %q may not be used on %s", name, loc)
+		}
+		if !dd.Repeatable && directives[:i].Get(name) != nil {
+			v.addErr(d.Name.Loc, "directive %q is not repeatable but used more than once at the same location", name)
+		}
+
+		for _, arg := range d.Args {
+			decl := dd.Args.Get(arg.Name.Name)
+			if decl == nil {
+				v.addErr(arg.Name.Loc, "invalid argument %q for directive %q", arg.Name.Name, name)
+				continue
+			}
+			if ok, reason := validateValue(arg.Value, decl.Type); !ok {
+				v.addErr(arg.Value.Location(), "invalid value %s for argument %q of directive %q: %s", arg.Value, arg.Name.Name, name, reason)
+			}
+		}
+		for _, decl := range dd.Args {
+			if _, ok := d.Args.Get(decl.Name.Name); ok {
+				continue
+			}
+			if _, required := decl.Type.(*common.NonNull); required && decl.Default == nil {
+				v.addErr(d.Name.Loc, "directive %q is missing the required argument %q", name, decl.Name.Name)
+			}
+			d.Args = append(d.Args, common.Argument{Name: decl.Name, Value: decl.Default})
+		}
+	}
+}
+
+// validateImplementations checks that the object or interface t correctly implements its
+// interfaces: it has to implement the interfaces of its interfaces as well and provide all their
+// fields with compatible types and arguments.
+func (v *validator) validateImplementations(t NamedType, fields FieldList, intfs []*Interface) {
+	loc := typeLocation(t)
+	for _, intf := range intfs {
+		if intf == t {
+			v.addErr(loc, "interface %q can not implement itself", intf.Name)
+			continue
+		}
+		for _, transitive := range intf.Interfaces {
+			if transitive == t {
+				v.addErr(loc, "interface %q can not implement itself", transitive.Name)
+			} else if !implements(intfs, transitive) {
+				v.addErr(loc, "type %q must implement %q because it is implemented by %q", t.TypeName(), transitive.Name, intf.Name)
+			}
+		}
+
+		for _, intfField := range intf.Fields {
+			f := fields.Get(intfField.Name)
+			if f == nil {
+				v.addErr(loc, "type %q does not implement %q: missing field %q", t.TypeName(), intf.Name, intfField.Name)
+				continue
+			}
+			if !isSubType(f.Type, intfField.Type) {
+				v.addErr(f.Loc, "field %q of type %q has type %q which is not a subtype of %q required by %q", f.Name, t.TypeName(), f.Type, intfField.Type, intf.Name)
+			}
+			for _, intfArg := range intfField.Args {
+				arg := f.Args.Get(intfArg.Name.Name)
+				if arg == nil || arg.Type.String() != intfArg.Type.String() {
+					v.addErr(f.Loc, "field %q of type %q must have argument %q of type %q required by %q", f.Name, t.TypeName(), intfArg.Name.Name, intfArg.Type, intf.Name)
+				}
+			}
+			for _, arg := range f.Args {
+				if _, required := arg.Type.(*common.NonNull); required && arg.Default == nil && intfField.Args.Get(arg.Name.Name) == nil {
+					v.addErr(arg.Loc, "field %q of type %q has required argument %q which is missing on %q", f.Name, t.TypeName(), arg.Name.Name, intf.Name)
+				}
+			}
+		}
+	}
+}
+
+func implements(intfs []*Interface, intf *Interface) bool {
+	for _, i := range intfs {
+		if i == intf {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubType reports whether a field of type sub satisfies a field of type t declared by an
+// interface.
+func isSubType(sub, t common.Type) bool {
+	if nn, ok := t.(*common.NonNull); ok {
+		subNN, ok := sub.(*common.NonNull)
+		return ok && isSubType(subNN.OfType, nn.OfType)
+	}
+	if nn, ok := sub.(*common.NonNull); ok {
+		return isSubType(nn.OfType, t)
+	}
+	if l, ok := t.(*common.List); ok {
+		subL, ok := sub.(*common.List)
+		return ok && isSubType(subL.OfType, l.OfType)
+	}
+	if sub == t {
+		return true
+	}
+
+	switch t := t.(type) {
+	case *Interface:
+		switch sub := sub.(type) {
+		case *Object:
+			return implements(sub.Interfaces, t)
+		case *Interface:
+			return implements(sub.Interfaces, t)
+		}
+	case *Union:
+		if sub, ok := sub.(*Object); ok {
+			for _, pt := range t.PossibleTypes {
+				if pt == sub {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// validateInputCycles rejects input types which can not be provided, since they contain themselves
+// through a chain of non-null fields.
+func (v *validator) validateInputCycles() {
+	visited := make(map[*InputObject]bool)
+	var path []*InputObject
+	var visit func(t *InputObject)
+	visit = func(t *InputObject) {
+		for i, prev := range path {
+			if prev == t {
+				names := make([]string, 0, len(path)-i+1)
+				for _, p := range path[i:] {
+					names = append(names, p.Name)
+				}
+				names = append(names, t.Name)
+				v.addErr(t.Loc, "input type %q references itself through non-null fields: %s", t.Name, strings.Join(names, " -> "))
+				return
+			}
+		}
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+
+		path = append(path, t)
+		for _, iv := range t.Values {
+			if nn, ok := iv.Type.(*common.NonNull); ok {
+				if field, ok := nn.OfType.(*InputObject); ok {
+					visit(field)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+	}
+
+	for _, t := range v.schema.definitions {
+		if t, ok := t.(*InputObject); ok && v.isDefinition(t) {
+			visit(t)
+		}
+	}
+}
+
+func isOutputType(t common.Type) bool {
+	switch t := t.(type) {
+	case *Scalar, *Object, *Interface, *Union, *Enum:
+		return true
+	case *common.List:
+		return isOutputType(t.OfType)
+	case *common.NonNull:
+		return isOutputType(t.OfType)
+	default:
+		return false
+	}
+}
+
+func isInputType(t common.Type) bool {
+	switch t := t.(type) {
+	case *Scalar, *Enum, *InputObject:
+		return true
+	case *common.List:
+		return isInputType(t.OfType)
+	case *common.NonNull:
+		return isInputType(t.OfType)
+	default:
+		return false
+	}
+}
+
+// validateValue checks a constant value against its input type.
+func validateValue(v common.Literal, t common.Type) (bool, string) {
+	_, isNull := v.(*common.NullLit)
+	if nn, ok := t.(*common.NonNull); ok {
+		if isNull {
+			return false, fmt.Sprintf("Expected %q, found null.", t)
+		}
+		t = nn.OfType
+	}
+	if isNull {
+		return true, ""
+	}
+
+	switch t := t.(type) {
+	case *Scalar, *Enum:
+		if lit, ok := v.(*common.BasicLit); ok && ValidateBasicLit(lit, t) {
+			return true, ""
+		}
+		if _, ok := v.(*common.BasicLit); !ok && IsCustomScalar(t) {
+			return true, ""
+		}
+
+	case *common.List:
+		list, ok := v.(*common.ListLit)
+		if !ok {
+			return validateValue(v, t.OfType) // single value instead of list
+		}
+		for i, entry := range list.Entries {
+			if ok, reason := validateValue(entry, t.OfType); !ok {
+				return false, fmt.Sprintf("In element #%d: %s", i, reason)
+			}
+		}
+		return true, ""
+
+	case *InputObject:
+		obj, ok := v.(*common.ObjectLit)
+		if !ok {
+			return false, fmt.Sprintf("Expected %q, found not an object.", t)
+		}
+		for _, f := range obj.Fields {
+			iv := t.Values.Get(f.Name.Name)
+			if iv == nil {
+				return false, fmt.Sprintf("In field %q: Unknown field.", f.Name.Name)
+			}
+			if ok, reason := validateValue(f.Value, iv.Type); !ok {
+				return false, fmt.Sprintf("In field %q: %s", f.Name.Name, reason)
+			}
+		}
+		for _, iv := range t.Values {
+			if _, required := iv.Type.(*common.NonNull); !required || iv.Default != nil {
+				continue
+			}
+			found := false
+			for _, f := range obj.Fields {
+				found = found || f.Name.Name == iv.Name.Name
+			}
+			if !found {
+				return false, fmt.Sprintf("In field %q: Expected %q, found null.", iv.Name.Name, iv.Type)
+			}
+		}
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("Expected type %q, found %s.", t, v)
+}
+
+// IsCustomScalar reports whether t is a scalar which is not built into GraphQL. The literals of
+// custom scalars may also be lists or objects.
+func IsCustomScalar(t common.Type) bool {
+	s, ok := t.(*Scalar)
+	return ok && Meta.Types[s.Name] != s
+}
+
+// ValidateBasicLit reports whether the literal is a valid value of the scalar or enum type.
+// Values of custom scalars are not checked.
+func ValidateBasicLit(v *common.BasicLit, t common.Type) bool {
+	switch t := t.(type) {
+	case *Scalar:
+		switch t.Name {
+		case "Int":
+			if v.Type != scanner.Int {
+				return false
+			}
+			f, err := strconv.ParseFloat(v.Text, 64)
+			if err != nil {
+				panic(err)
+			}
+			return f >= math.MinInt32 && f <= math.MaxInt32
+		case "Float":
+			return v.Type == scanner.Int || v.Type == scanner.Float
+		case "String":
+			return v.Type == scanner.String
+		case "Boolean":
+			return v.Type == scanner.Ident && (v.Text == "true" || v.Text == "false")
+		case "ID":
+			return v.Type == scanner.Int || v.Type == scanner.String
+		default:
+			//TODO: Type-check against expected type by Unmarshalling
+			return true
+		}
+
+	case *Enum:
+		if v.Type != scanner.Ident {
+			return false
+		}
+		for _, option := range t.Values {
+			if option.Name == v.Text {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
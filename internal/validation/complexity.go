@@ -0,0 +1,177 @@
+package validation
+
+import (
+	"math"
+
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/query"
+	"github.com/sevlyar/graphql-go/internal/schema"
+)
+
+// defaultMultipliers are the arguments which limit the length of a list if the field has no @cost
+// directive listing its multipliers.
+var defaultMultipliers = []string{"first", "last", "limit"}
+
+type complexityContext struct {
+	schema    *schema.Schema
+	doc       *query.Document
+	vars      map[string]interface{}
+	fragCosts map[*query.FragmentDecl]int
+}
+
+// Complexity returns the cost of executing the validated operation. Every field costs the
+// complexity given with its @cost directive, 1 by default, plus the costs of its subfields. This
+// sum is multiplied by the values of the arguments listed as multipliers of @cost. Lists without
+// @cost are multiplied by their "first", "last" or "limit" argument. The result is an upper bound,
+// since all fragments are counted, even if their type conditions exclude each other.
+func Complexity(s *schema.Schema, doc *query.Document, op *query.Operation, vars map[string]interface{}) int {
+	c := &complexityContext{
+		schema:    s,
+		doc:       doc,
+		vars:      vars,
+		fragCosts: make(map[*query.FragmentDecl]int),
+	}
+
+	var entryPoint schema.NamedType
+	switch op.Type {
+	case query.Query:
+		entryPoint = s.EntryPoints["query"]
+	case query.Mutation:
+		entryPoint = s.EntryPoints["mutation"]
+	case query.Subscription:
+		entryPoint = s.EntryPoints["subscription"]
+	}
+	return c.selectionsCost(op.Selections, entryPoint)
+}
+
+func (c *complexityContext) selectionsCost(sels []query.Selection, t schema.NamedType) int {
+	cost := 0
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *query.Field:
+			cost = addCost(cost, c.fieldCost(sel, t))
+
+		case *query.InlineFragment:
+			ft := t
+			if sel.On.Name != "" {
+				ft = c.schema.Types[sel.On.Name]
+			}
+			cost = addCost(cost, c.selectionsCost(sel.Selections, ft))
+
+		case *query.FragmentSpread:
+			frag := c.doc.Fragments.Get(sel.Name.Name)
+			if frag == nil {
+				continue
+			}
+			fc, ok := c.fragCosts[frag]
+			if !ok {
+				fc = c.selectionsCost(frag.Selections, c.schema.Types[frag.On.Name])
+				c.fragCosts[frag] = fc
+			}
+			cost = addCost(cost, fc)
+
+		default:
+			panic("unreachable")
+		}
+	}
+	return cost
+}
+
+func (c *complexityContext) fieldCost(sel *query.Field, t schema.NamedType) int {
+	var f *schema.Field
+	switch sel.Name.Name {
+	case "__typename":
+		return 0
+	case "__schema":
+		f = &schema.Field{Name: "__schema", Type: c.schema.Types["__Schema"]}
+	case "__type":
+		f = &schema.Field{Name: "__type", Type: c.schema.Types["__Type"]}
+	default:
+		f = fields(t).Get(sel.Name.Name)
+	}
+	if f == nil {
+		return 1
+	}
+
+	complexity := 1
+	var multipliers []string
+	if d := f.Directives.Get("cost"); d != nil {
+		if v, ok := directiveArg(d, "complexity").(float64); ok {
+			complexity = int(v)
+		}
+		if l, ok := directiveArg(d, "multipliers").([]interface{}); ok {
+			for _, name := range l {
+				if name, ok := name.(string); ok {
+					multipliers = append(multipliers, name)
+				}
+			}
+		}
+	} else if isList(f.Type) {
+		multipliers = defaultMultipliers
+	}
+
+	cost := addCost(complexity, c.selectionsCost(sel.Selections, unwrapType(f.Type)))
+	for _, name := range multipliers {
+		if n, ok := c.fieldArg(sel, f, name); ok {
+			cost = mulCost(cost, n)
+		}
+	}
+	return cost
+}
+
+// directiveArg returns the value of the directive's argument. The schema already filled in the
+// defaults of missing arguments.
+func directiveArg(d *common.Directive, name string) interface{} {
+	if lit, ok := d.Args.Get(name); ok && lit != nil {
+		return lit.Value(nil)
+	}
+	return nil
+}
+
+// fieldArg returns the numeric value of the argument of the selected field, falling back to the
+// default of the argument's declaration.
+func (c *complexityContext) fieldArg(sel *query.Field, f *schema.Field, name string) (int, bool) {
+	var v interface{}
+	if lit, ok := sel.Arguments.Get(name); ok {
+		v = lit.Value(c.vars)
+	} else if arg := f.Args.Get(name); arg != nil && arg.Default != nil {
+		v = arg.Default.Value(nil)
+	}
+
+	var n float64
+	switch v := v.(type) {
+	case float64:
+		n = v
+	case int32:
+		n = float64(v)
+	case int:
+		n = float64(v)
+	default:
+		return 0, false
+	}
+	return int(math.Min(math.Max(n, 0), math.MaxInt32)), true
+}
+
+func isList(t common.Type) bool {
+	if nn, ok := t.(*common.NonNull); ok {
+		t = nn.OfType
+	}
+	_, ok := t.(*common.List)
+	return ok
+}
+
+// addCost and mulCost saturate instead of overflowing, so huge multipliers can not wrap around to
+// a small cost.
+func addCost(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
+
+func mulCost(a, b int) int {
+	if b != 0 && a > math.MaxInt32/b {
+		return math.MaxInt32
+	}
+	return a * b
+}
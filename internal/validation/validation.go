@@ -2,11 +2,8 @@ package validation
 
 import (
 	"fmt"
-	"math"
 	"reflect"
-	"strconv"
 	"strings"
-	"text/scanner"
 
 	"github.com/sevlyar/graphql-go/errors"
 	"github.com/sevlyar/graphql-go/internal/common"
@@ -25,6 +22,7 @@ type fieldInfo struct {
 
 type context struct {
 	schema           *schema.Schema
+	maxDepth         int
 	doc              *query.Document
 	errs             []*errors.QueryError
 	opErrs           map[*query.Operation][]*errors.QueryError
@@ -50,9 +48,19 @@ type opContext struct {
 	ops []*query.Operation
 }
 
-func Validate(s *schema.Schema, doc *query.Document) []*errors.QueryError {
+// Options holds the limits checked by Validate in addition to the rules of the specification. The
+// zero value disables all of them. The complexity of an operation depends on its variables, so it
+// is computed by Complexity at execution time instead.
+type Options struct {
+	// MaxDepth rejects operations selecting fields nested deeper than MaxDepth, if greater than zero.
+	MaxDepth int
+}
+
+// Validate validates the document against the schema and the limits of opts.
+func Validate(s *schema.Schema, doc *query.Document, opts Options) []*errors.QueryError {
 	c := &context{
 		schema:           s,
+		maxDepth:         opts.MaxDepth,
 		doc:              doc,
 		opErrs:           make(map[*query.Operation][]*errors.QueryError),
 		usedVars:         make(map[*query.Operation]varSet),
@@ -62,6 +70,7 @@ func Validate(s *schema.Schema, doc *query.Document) []*errors.QueryError {
 
 	opNames := make(nameSet)
 	fragUsedBy := make(map[*query.FragmentDecl][]*query.Operation)
+	fragDepths := make(map[*query.FragmentDecl]int)
 	for _, op := range doc.Operations {
 		c.usedVars[op] = make(varSet)
 		opc := &opContext{c, []*query.Operation{op}}
@@ -78,6 +87,7 @@ func Validate(s *schema.Schema, doc *query.Document) []*errors.QueryError {
 		varNames := make(nameSet)
 		for _, v := range op.Vars {
 			validateName(c, varNames, v.Name, "UniqueVariableNames", "variable")
+			validateDirectives(opc, "VARIABLE_DEFINITION", v.Directives)
 
 			t := resolveType(c, v.Type)
 			if !canBeInput(t) {
@@ -111,6 +121,7 @@ func Validate(s *schema.Schema, doc *query.Document) []*errors.QueryError {
 			panic("unreachable")
 		}
 
+		validateMaxDepth(opc, op.Selections, fragDepths)
 		validateSelectionSet(opc, op.Selections, entryPoint)
 
 		fragUsed := make(map[*query.FragmentDecl]struct{})
@@ -296,6 +307,81 @@ func possibleTypes(t common.Type) []*schema.Object {
 	}
 }
 
+// validateMaxDepth reports the first field which is nested deeper than the maximum depth, counting
+// fragment spreads as if their selections were inlined.
+func validateMaxDepth(c *opContext, sels []query.Selection, fragDepths map[*query.FragmentDecl]int) {
+	if c.maxDepth <= 0 || selectionsDepth(c.context, sels, fragDepths) <= c.maxDepth {
+		return
+	}
+	findTooDeepField(c, sels, fragDepths, 1)
+}
+
+// selectionsDepth returns the depth of the selections, with the depths of fragments cached in
+// fragDepths. Cyclic fragments are reported by another rule and count as depth 0.
+func selectionsDepth(c *context, sels []query.Selection, fragDepths map[*query.FragmentDecl]int) int {
+	depth := 0
+	for _, sel := range sels {
+		d := 0
+		switch sel := sel.(type) {
+		case *query.Field:
+			d = 1 + selectionsDepth(c, sel.Selections, fragDepths)
+
+		case *query.InlineFragment:
+			d = selectionsDepth(c, sel.Selections, fragDepths)
+
+		case *query.FragmentSpread:
+			frag := c.doc.Fragments.Get(sel.Name.Name)
+			if frag == nil {
+				continue
+			}
+			fd, ok := fragDepths[frag]
+			if !ok {
+				fragDepths[frag] = 0 // guards against cycles
+				fd = selectionsDepth(c, frag.Selections, fragDepths)
+				fragDepths[frag] = fd
+			}
+			d = fd
+
+		default:
+			panic("unreachable")
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+func findTooDeepField(c *opContext, sels []query.Selection, fragDepths map[*query.FragmentDecl]int, level int) bool {
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *query.Field:
+			if level > c.maxDepth {
+				c.addErr(sel.Alias.Loc, "MaxDepth", "Field %q exceeds the maximum query depth of %d.", sel.Name.Name, c.maxDepth)
+				return true
+			}
+			if level+selectionsDepth(c.context, sel.Selections, fragDepths) > c.maxDepth && findTooDeepField(c, sel.Selections, fragDepths, level+1) {
+				return true
+			}
+
+		case *query.InlineFragment:
+			if findTooDeepField(c, sel.Selections, fragDepths, level) {
+				return true
+			}
+
+		case *query.FragmentSpread:
+			frag := c.doc.Fragments.Get(sel.Name.Name)
+			if frag != nil && level-1+fragDepths[frag] > c.maxDepth && findTooDeepField(c, frag.Selections, fragDepths, level) {
+				return true
+			}
+
+		default:
+			panic("unreachable")
+		}
+	}
+	return false
+}
+
 func markUsedFragments(c *context, sels []query.Selection, fragUsed map[*query.FragmentDecl]struct{}) {
 	for _, sel := range sels {
 		switch sel := sel.(type) {
@@ -533,26 +619,21 @@ func validateDirectives(c *opContext, loc string, directives common.DirectiveLis
 	directiveNames := make(nameSet)
 	for _, d := range directives {
 		dirName := d.Name.Name
-		validateNameCustomMsg(c.context, directiveNames, d.Name, "UniqueDirectivesPerLocation", func() string {
-			return fmt.Sprintf("The directive %q can only be used once at this location.", dirName)
-		})
+		dd, ok := c.schema.Directives[dirName]
+		if !ok || !dd.Repeatable {
+			validateNameCustomMsg(c.context, directiveNames, d.Name, "UniqueDirectivesPerLocation", func() string {
+				return fmt.Sprintf("The directive %q can only be used once at this location.", dirName)
+			})
+		}
 
 		validateArgumentLiterals(c, d.Args)
 
-		dd, ok := c.schema.Directives[dirName]
 		if !ok {
 			c.addErr(d.Name.Loc, "KnownDirectives", "Unknown directive %q.", dirName)
 			continue
 		}
 
-		locOK := false
-		for _, allowedLoc := range dd.Locs {
-			if loc == allowedLoc {
-				locOK = true
-				break
-			}
-		}
-		if !locOK {
+		if !dd.HasLocation(loc) {
 			c.addErr(d.Name.Loc, "KnownDirectives", "Directive %q may not be used on %s.", dirName, loc)
 		}
 
@@ -594,7 +675,7 @@ func validateArgumentTypes(c *opContext, args common.ArgumentList, argDecls comm
 		}
 	}
 	for _, decl := range argDecls {
-		if _, ok := decl.Type.(*common.NonNull); ok {
+		if _, ok := decl.Type.(*common.NonNull); ok && decl.Default == nil {
 			if _, ok := args.Get(decl.Name.Name); !ok {
 				c.addErr(loc, "ProvidedNonNullArguments", "%s argument %q of type %q is required but not provided.", owner2(), decl.Name.Name, decl.Type)
 			}
@@ -671,9 +752,11 @@ func validateValueType(c *opContext, v common.Literal, t common.Type) (bool, str
 	switch t := t.(type) {
 	case *schema.Scalar, *schema.Enum:
 		if lit, ok := v.(*common.BasicLit); ok {
-			if validateBasicLit(lit, t) {
+			if schema.ValidateBasicLit(lit, t) {
 				return true, ""
 			}
+		} else if schema.IsCustomScalar(t) {
+			return true, "" // lists and objects are checked by the custom scalar itself
 		}
 
 	case *common.List:
@@ -723,47 +806,6 @@ func validateValueType(c *opContext, v common.Literal, t common.Type) (bool, str
 	return false, fmt.Sprintf("Expected type %q, found %s.", t, v)
 }
 
-func validateBasicLit(v *common.BasicLit, t common.Type) bool {
-	switch t := t.(type) {
-	case *schema.Scalar:
-		switch t.Name {
-		case "Int":
-			if v.Type != scanner.Int {
-				return false
-			}
-			f, err := strconv.ParseFloat(v.Text, 64)
-			if err != nil {
-				panic(err)
-			}
-			return f >= math.MinInt32 && f <= math.MaxInt32
-		case "Float":
-			return v.Type == scanner.Int || v.Type == scanner.Float
-		case "String":
-			return v.Type == scanner.String
-		case "Boolean":
-			return v.Type == scanner.Ident && (v.Text == "true" || v.Text == "false")
-		case "ID":
-			return v.Type == scanner.Int || v.Type == scanner.String
-		default:
-			//TODO: Type-check against expected type by Unmarshalling
-			return true
-		}
-
-	case *schema.Enum:
-		if v.Type != scanner.Ident {
-			return false
-		}
-		for _, option := range t.Values {
-			if option.Name == v.Text {
-				return true
-			}
-		}
-		return false
-	}
-
-	return false
-}
-
 func canBeFragment(t common.Type) bool {
 	switch t.(type) {
 	case *schema.Object, *schema.Interface, *schema.Union:
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/sevlyar/graphql-go/internal/exec/resolvable"
+	"github.com/sevlyar/graphql-go/internal/query"
 	"github.com/sevlyar/graphql-go/introspection"
 )
 
@@ -13,9 +14,12 @@ func (s *Schema) Inspect() *introspection.Schema {
 	return introspection.WrapSchema(s.schema)
 }
 
-// ToJSON encodes the schema in a JSON format used by tools like Relay.
+// ToJSON encodes the schema in a JSON format used by tools like Relay. The introspection query is
+// part of the package, so it is neither restricted to the trusted documents nor to the limits of
+// the schema, e.g. MaxDepth is usually lower than the nesting of its type references.
 func (s *Schema) ToJSON() ([]byte, error) {
-	result := s.exec(context.Background(), introspectionQuery, "", nil, &resolvable.Schema{
+	doc := introspectionDoc
+	result := s.execOperation(context.Background(), doc, doc.Operations[0], introspectionQuery, "", nil, &resolvable.Schema{
 		Query:  &resolvable.Object{},
 		Schema: *s.schema,
 	})
@@ -25,6 +29,32 @@ func (s *Schema) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(result.Data, "", "\t")
 }
 
+// ParseIntrospection builds a schema from the result of an introspection query, as returned by
+// ToJSON. The whole response with a "data" key is accepted as well. The resolver and options work
+// like with ParseSchema; with a nil resolver the schema can still be used to validate queries.
+func ParseIntrospection(introspectionJSON []byte, resolver interface{}, opts ...SchemaOpt) (*Schema, error) {
+	s := newSchema(opts)
+	if err := s.schema.ParseIntrospection(introspectionJSON); err != nil {
+		return nil, err
+	}
+	if err := s.prepare(resolver); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// introspectionDoc is the parsed introspectionQuery.
+var introspectionDoc = mustParse(introspectionQuery)
+
+// mustParse parses a query which is part of the package.
+func mustParse(queryString string) *query.Document {
+	doc, err := query.Parse(queryString)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
 var introspectionQuery = `
   query {
     __schema {
@@ -41,6 +71,7 @@ var introspectionQuery = `
         args {
           ...InputValue
         }
+        isRepeatable
       }
     }
   }
@@ -48,6 +79,7 @@ var introspectionQuery = `
     kind
     name
     description
+    specifiedByURL
     fields(includeDeprecated: true) {
       name
       description
@@ -115,3 +147,10 @@ var introspectionQuery = `
     }
   }
 `
+
+// ToSDL returns the schema in the Schema Definition Language. Types and directive declarations are
+// sorted by name, so the result is suitable for committing and diffing. Builtin types and
+// directives are omitted.
+func (s *Schema) ToSDL() string {
+	return s.schema.ToSDL()
+}
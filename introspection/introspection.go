@@ -100,6 +100,19 @@ func (r *Type) Description() *string {
 	return nil
 }
 
+func (r *Type) SpecifiedByURL() *string {
+	t, ok := r.typ.(*schema.Scalar)
+	if !ok {
+		return nil
+	}
+	d := t.Directives.Get("specifiedBy")
+	if d == nil {
+		return nil
+	}
+	url := d.Args.MustGet("url").Value(nil).(string)
+	return &url
+}
+
 func (r *Type) Fields(args *struct{ IncludeDeprecated bool }) *[]*Field {
 	var fields schema.FieldList
 	switch t := r.typ.(type) {
@@ -121,13 +134,18 @@ func (r *Type) Fields(args *struct{ IncludeDeprecated bool }) *[]*Field {
 }
 
 func (r *Type) Interfaces() *[]*Type {
-	t, ok := r.typ.(*schema.Object)
-	if !ok {
+	var interfaces []*schema.Interface
+	switch t := r.typ.(type) {
+	case *schema.Object:
+		interfaces = t.Interfaces
+	case *schema.Interface:
+		interfaces = t.Interfaces
+	default:
 		return nil
 	}
 
-	l := make([]*Type, len(t.Interfaces))
-	for i, intf := range t.Interfaces {
+	l := make([]*Type, len(interfaces))
+	for i, intf := range interfaces {
 		l[i] = &Type{intf}
 	}
 	return &l
@@ -304,6 +322,10 @@ func (r *Directive) Locations() []string {
 	return r.directive.Locs
 }
 
+func (r *Directive) IsRepeatable() bool {
+	return r.directive.Repeatable
+}
+
 func (r *Directive) Args() []*InputValue {
 	l := make([]*InputValue, len(r.directive.Args))
 	for i, v := range r.directive.Args {
@@ -0,0 +1,136 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+func TestParseIntrospection(t *testing.T) {
+	for _, schema := range []string{starwars.Schema, interfacesSchema, `
+		schema {
+			query: Query
+		}
+
+		"Repeatable marker."
+		directive @mark("Shown in traces." label: String = "x") repeatable on FIELD
+
+		type Query {
+			list("Filters the values." filter: Filter = {ids: [1, 2]}, mode: Mode = OFF): [[Int!]]!
+		}
+
+		input Filter {
+			"""
+			The IDs to keep,
+			all if absent.
+			"""
+			ids: [Int!]
+		}
+
+		enum Mode {
+			ON
+			OFF @deprecated
+		}
+
+		scalar Big @specifiedBy(url: "https://example.com/big")
+	`} {
+		data, err := graphql.MustParseSchema(schema, nil).ToJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := graphql.ParseIntrospection(data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := graphql.MustParseSchema(schema, nil).ToSDL()
+		if got := s.ToSDL(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
+
+func TestParseIntrospectionExecutable(t *testing.T) {
+	data, err := graphql.MustParseSchema(starwars.Schema, nil).ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a full response, as fetched from a remote service
+	response, err := json.Marshal(map[string]json.RawMessage{"data": data})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := graphql.ParseIntrospection(response, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := validator.Validate(`{ hero { name } }`); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs := validator.Validate(`{ hero { age } }`); len(errs) != 1 {
+		t.Errorf("got errors %v, want one error", errs)
+	}
+
+	s, err := graphql.ParseIntrospection(data, &starwars.Resolver{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gqltesting.RunTest(t, &gqltesting.Test{
+		Schema: s,
+		Query: `
+			{
+				hero(episode: EMPIRE) {
+					name
+				}
+			}
+		`,
+		ExpectedResult: `
+			{
+				"hero": {
+					"name": "Luke Skywalker"
+				}
+			}
+		`,
+	})
+}
+
+func TestParseIntrospectionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "invalid json",
+			json: `{`,
+			want: `invalid introspection result`,
+		},
+		{
+			name: "missing schema",
+			json: `{"data": {}}`,
+			want: `missing "__schema"`,
+		},
+		{
+			name: "unknown kind",
+			json: `{"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "THING", "name": "Query"}]}}`,
+			want: `type "Query" has the unknown kind "THING"`,
+		},
+		{
+			name: "unknown type",
+			json: `{"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query", "fields": [{"name": "a", "type": {"kind": "OBJECT", "name": "Missing"}}]}]}}`,
+			want: `Unknown type "Missing"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseIntrospection([]byte(tt.json), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sevlyar/graphql-go/internal/exec"
+)
+
+// FieldInfo describes the field whose resolver is called.
+type FieldInfo struct {
+	TypeName  string
+	FieldName string
+	Args      map[string]interface{}
+	Path      []interface{}
+}
+
+// FieldResolver calls the next middleware or, at the end of the chain, the resolver.
+type FieldResolver func(ctx context.Context) (interface{}, error)
+
+// FieldMiddleware wraps the call of a field's resolver. It may call next, optionally with a
+// modified context, and return or alter its result. Returning without calling next short-circuits
+// the resolver; a returned value has to be assignable to the result type of the resolver method,
+// nil only if that is a pointer or interface, and a returned error is reported like an error of
+// the resolver.
+type FieldMiddleware func(ctx context.Context, field *FieldInfo, next FieldResolver) (interface{}, error)
+
+// Middleware adds middlewares which are called around every resolver method, e.g. for
+// authorization, metrics or caching. The first middleware is the outermost one. Fields which are
+// resolved without calling a method, like __typename, do not pass the middlewares.
+func Middleware(middlewares ...FieldMiddleware) SchemaOpt {
+	return func(s *Schema) {
+		s.middlewares = append(s.middlewares, middlewares...)
+	}
+}
+
+// execMiddleware chains the middlewares and field directives of the schema for the executor.
+func (s *Schema) execMiddleware() exec.Middleware {
+	if len(s.middlewares) == 0 && len(s.fieldDirectives) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, typeName string, fieldName string, args map[string]interface{}, path []interface{}, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		field := &FieldInfo{TypeName: typeName, FieldName: fieldName, Args: args, Path: path}
+		resolve := chainMiddlewares(s.fieldDirectives[fieldKey{typeName, fieldName}], field, next)
+		resolve = chainMiddlewares(s.middlewares, field, resolve)
+		return resolve(ctx)
+	}
+}
+
+// chainMiddlewares wraps next with the middlewares, the first one being the outermost.
+func chainMiddlewares(middlewares []FieldMiddleware, field *FieldInfo, next FieldResolver) FieldResolver {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw, inner := middlewares[i], next
+		next = func(ctx context.Context) (interface{}, error) {
+			return mw(ctx, field, inner)
+		}
+	}
+	return next
+}
@@ -0,0 +1,78 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+)
+
+func TestMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	logCalls := func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		mu.Lock()
+		calls = append(calls, fmt.Sprintf("%s.%s %v %v", field.TypeName, field.FieldName, field.Path, field.Args))
+		mu.Unlock()
+		return next(ctx)
+	}
+	authorize := func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		switch {
+		case field.TypeName == "Human" && field.FieldName == "mass":
+			return nil, errors.New("not authorized")
+		case field.TypeName == "Human" && field.FieldName == "name":
+			v, err := next(ctx)
+			return "Agent " + v.(string), err
+		}
+		return next(ctx)
+	}
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.Middleware(logCalls, authorize))
+
+	result := s.Exec(context.Background(), `{ human(id: "1000") { name mass } }`, "", nil)
+	if string(result.Data) != `{"human":{"name":"Agent Luke Skywalker","mass":null}}` {
+		t.Errorf("unexpected data %s", result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "not authorized" || fmt.Sprint(result.Errors[0].Path) != "[human mass]" {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+
+	want := []string{"Query.human [human] map[id:1000]", "Human.name [human name] map[]", "Human.mass [human mass] map[]"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("got calls %q, want %q", calls, want)
+	}
+}
+
+func TestMiddlewareWrongType(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.Middleware(func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		if field.FieldName == "name" {
+			return 42, nil
+		}
+		return next(ctx)
+	}))
+
+	result := s.Exec(context.Background(), `{ hero { id name } }`, "", nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != `middleware returned int for field "name", want string` {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+}
+
+func TestMiddlewareNil(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.Middleware(func(ctx context.Context, field *graphql.FieldInfo, next graphql.FieldResolver) (interface{}, error) {
+		if field.FieldName == "name" || field.FieldName == "mass" {
+			return nil, nil
+		}
+		return next(ctx)
+	}))
+
+	result := s.Exec(context.Background(), `{ human(id: "1000") { mass } hero { id name } }`, "", nil)
+	if string(result.Data) != `{"human":{"mass":null},"hero":null}` {
+		t.Errorf("unexpected data %s", result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != `middleware returned nil for field "name", want string` || fmt.Sprint(result.Errors[0].Path) != "[hero name]" {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+}
@@ -0,0 +1,64 @@
+package graphql
+
+// The Null types can be used for nullable arguments and fields of input objects which have to tell
+// an absent value from an explicit null, e.g. for mutations which only update the given fields:
+//
+//	func (r *Resolver) UpdateUser(args struct{ ID graphql.ID; Email graphql.NullString }) *userResolver {
+//		if args.Email.Set {
+//			// update the email, clear it if args.Email.Value is nil
+//		}
+//		...
+//	}
+//
+// Set is false if the value is absent or given as variable which is not provided, Value is nil if
+// the value is null. A default value counts as set. Other types, e.g. for input objects, enums or
+// lists, can be declared the same way: a struct with a pointer field Value, a bool field Set and
+// the method Nullable.
+
+// NullString is a nullable String which records whether it was set.
+type NullString struct {
+	Value *string
+	Set   bool
+}
+
+func (NullString) Nullable() {}
+
+// NullInt is a nullable Int which records whether it was set.
+type NullInt struct {
+	Value *int32
+	Set   bool
+}
+
+func (NullInt) Nullable() {}
+
+// NullFloat is a nullable Float which records whether it was set.
+type NullFloat struct {
+	Value *float64
+	Set   bool
+}
+
+func (NullFloat) Nullable() {}
+
+// NullBool is a nullable Boolean which records whether it was set.
+type NullBool struct {
+	Value *bool
+	Set   bool
+}
+
+func (NullBool) Nullable() {}
+
+// NullID is a nullable ID which records whether it was set.
+type NullID struct {
+	Value *ID
+	Set   bool
+}
+
+func (NullID) Nullable() {}
+
+// NullTime is a nullable Time which records whether it was set.
+type NullTime struct {
+	Value *Time
+	Set   bool
+}
+
+func (NullTime) Nullable() {}
@@ -0,0 +1,165 @@
+package graphql_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const nullableSchema = `
+	schema {
+		query: Query
+		mutation: Mutation
+	}
+
+	type Query {
+		ping: String!
+	}
+
+	type Mutation {
+		updateUser(id: ID!, name: String, age: Int = 42, patch: UserPatch): String!
+	}
+
+	input UserPatch {
+		email: String
+		tags: [String!]
+	}
+`
+
+// nullTags is a wrapper declared outside of the graphql package.
+type nullTags struct {
+	Value *[]string
+	Set   bool
+}
+
+func (nullTags) Nullable() {}
+
+type userPatch struct {
+	Email graphql.NullString
+	Tags  nullTags
+}
+
+type nullableResolver struct{}
+
+func (r *nullableResolver) Ping() string {
+	return "pong"
+}
+
+func (r *nullableResolver) UpdateUser(args struct {
+	ID    graphql.ID
+	Name  graphql.NullString
+	Age   graphql.NullInt
+	Patch *userPatch
+}) string {
+	changes := []string{"id=" + string(args.ID)}
+	if args.Name.Set {
+		changes = append(changes, "name="+describe(args.Name.Value))
+	}
+	if args.Age.Set {
+		changes = append(changes, "age="+describe(args.Age.Value))
+	}
+	if p := args.Patch; p != nil {
+		if p.Email.Set {
+			changes = append(changes, "email="+describe(p.Email.Value))
+		}
+		if p.Tags.Set {
+			changes = append(changes, "tags="+describe(p.Tags.Value))
+		}
+	}
+	return strings.Join(changes, " ")
+}
+
+func describe(v interface{}) string {
+	switch v := v.(type) {
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *int32:
+		if v != nil {
+			return fmt.Sprint(*v)
+		}
+	case *[]string:
+		if v != nil {
+			return strings.Join(*v, ",")
+		}
+	}
+	return "null"
+}
+
+func TestNullable(t *testing.T) {
+	s := graphql.MustParseSchema(nullableSchema, &nullableResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				mutation {
+					absent: updateUser(id: "1")
+					null: updateUser(id: "2", name: null, age: null)
+					value: updateUser(id: "3", name: "Alice", age: 30)
+					patch: updateUser(id: "4", patch: {email: null, tags: ["a", "b"]})
+					emptyPatch: updateUser(id: "5", patch: {})
+				}
+			`,
+			ExpectedResult: `
+				{
+					"absent": "id=1 age=42",
+					"null": "id=2 name=null age=null",
+					"value": "id=3 name=Alice age=30",
+					"patch": "id=4 age=42 email=null tags=a,b",
+					"emptyPatch": "id=5 age=42"
+				}
+			`,
+		},
+		{
+			Schema: s,
+			Query: `
+				mutation($name: String, $age: Int, $email: String, $patch: UserPatch) {
+					variables: updateUser(id: "1", name: $name, age: $age, patch: {email: $email})
+					object: updateUser(id: "2", patch: $patch)
+				}
+			`,
+			Variables: map[string]interface{}{
+				"name":  nil,
+				"email": "alice@example.com",
+				"patch": map[string]interface{}{"tags": nil},
+			},
+			ExpectedResult: `
+				{
+					"variables": "id=1 name=null age=42 email=alice@example.com",
+					"object": "id=2 age=42 tags=null"
+				}
+			`,
+		},
+	})
+}
+
+type invalidWrapper struct {
+	Value *string
+}
+
+func (invalidWrapper) Nullable() {}
+
+type invalidNullableResolver struct {
+	nullableResolver
+}
+
+func (r *invalidNullableResolver) UpdateUser(args struct {
+	ID    graphql.ID
+	Name  invalidWrapper
+	Age   graphql.NullInt
+	Patch *userPatch
+}) string {
+	return ""
+}
+
+func TestNullableErrors(t *testing.T) {
+	_, err := graphql.ParseSchema(nullableSchema, &invalidNullableResolver{})
+	if err == nil || !strings.Contains(err.Error(), "must have a bool field Set") {
+		t.Errorf("got error %v", err)
+	}
+}
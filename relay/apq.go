@@ -0,0 +1,91 @@
+package relay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/lru"
+)
+
+// PersistedQueryStore stores the query strings of Automatic Persisted Queries by the hex encoded
+// SHA-256 hash of the query string. Implementations have to be safe for concurrent use.
+type PersistedQueryStore interface {
+	Get(ctx context.Context, hash string) (queryString string, ok bool)
+	Put(ctx context.Context, hash string, queryString string)
+}
+
+// NewInMemoryPersistedQueryStore returns a store which keeps at most size queries in memory. The
+// least recently used query is evicted first.
+func NewInMemoryPersistedQueryStore(size int) PersistedQueryStore {
+	return &inMemoryPersistedQueryStore{queries: lru.New(size)}
+}
+
+type inMemoryPersistedQueryStore struct {
+	queries *lru.Cache
+}
+
+func (s *inMemoryPersistedQueryStore) Get(ctx context.Context, hash string) (string, bool) {
+	q, ok := s.queries.Get(hash)
+	if !ok {
+		return "", false
+	}
+	return q.(string), true
+}
+
+func (s *inMemoryPersistedQueryStore) Put(ctx context.Context, hash string, queryString string) {
+	s.queries.Add(hash, queryString)
+}
+
+type persistedQuery struct {
+	Version    int    `json:"version"`
+	SHA256Hash string `json:"sha256Hash"`
+}
+
+func persistedQueryError(message string, code string) *errors.QueryError {
+	err := errors.Errorf("%s", message)
+	err.Extensions = map[string]interface{}{"code": code}
+	return err
+}
+
+// resolvePersistedQuery fills in the query string of a request referring to a persisted query, or
+// registers the query if the request carries both, following the protocol of Apollo's Automatic
+// Persisted Queries. Hashes of trusted documents are always known.
+func (h *Handler) resolvePersistedQuery(ctx context.Context, params *params) *errors.QueryError {
+	pq := params.Extensions.PersistedQuery
+	if pq == nil {
+		return nil
+	}
+	if pq.Version != 1 {
+		return errors.Errorf("unsupported persisted query version %d", pq.Version)
+	}
+	hash := strings.ToLower(pq.SHA256Hash)
+
+	if params.Query == "" {
+		if q, ok := h.Schema.TrustedDocument(hash); ok {
+			params.Query = q
+			return nil
+		}
+	}
+	if h.PersistedQueries == nil {
+		return persistedQueryError("PersistedQueryNotSupported", "PERSISTED_QUERY_NOT_SUPPORTED")
+	}
+
+	if params.Query == "" {
+		q, ok := h.PersistedQueries.Get(ctx, hash)
+		if !ok {
+			return persistedQueryError("PersistedQueryNotFound", "PERSISTED_QUERY_NOT_FOUND")
+		}
+		params.Query = q
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(params.Query))
+	if hex.EncodeToString(sum[:]) != hash {
+		return errors.Errorf("provided sha256Hash does not match query")
+	}
+	h.PersistedQueries.Put(ctx, hash, params.Query)
+	return nil
+}
@@ -0,0 +1,91 @@
+package relay_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+	"github.com/sevlyar/graphql-go/relay"
+)
+
+func servePersistedQuery(t *testing.T, h *relay.Handler, body string) string {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/some/path/here", strings.NewReader(body))
+	h.ServeHTTP(w, r)
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d.", w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestPersistedQuery(t *testing.T) {
+	h := &relay.Handler{Schema: starwarsSchema, PersistedQueries: relay.NewInMemoryPersistedQueryStore(10)}
+	query := "{ hero { name } }"
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	extensions := `"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}`
+
+	got := servePersistedQuery(t, h, `{`+extensions+`}`)
+	want := `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+
+	got = servePersistedQuery(t, h, `{"query":"`+query+`",`+extensions+`}`)
+	want = `{"data":{"hero":{"name":"R2-D2"}}}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+
+	got = servePersistedQuery(t, h, `{`+extensions+`}`)
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+}
+
+func TestPersistedQueryHashMismatch(t *testing.T) {
+	h := &relay.Handler{Schema: starwarsSchema, PersistedQueries: relay.NewInMemoryPersistedQueryStore(10)}
+
+	got := servePersistedQuery(t, h, `{"query":"{ hero { name } }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}}}`)
+	want := `{"errors":[{"message":"provided sha256Hash does not match query"}]}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+}
+
+func TestPersistedQueryNotSupported(t *testing.T) {
+	h := &relay.Handler{Schema: starwarsSchema}
+
+	got := servePersistedQuery(t, h, `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}}}`)
+	want := `{"errors":[{"message":"PersistedQueryNotSupported","extensions":{"code":"PERSISTED_QUERY_NOT_SUPPORTED"}}]}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+}
+
+func TestTrustedDocumentID(t *testing.T) {
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.TrustedDocuments([]byte(`{"hero": "{ hero { name } }"}`)))
+	h := &relay.Handler{Schema: s}
+
+	got := servePersistedQuery(t, h, `{"documentId":"hero"}`)
+	want := `{"data":{"hero":{"name":"R2-D2"}}}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+
+	got = servePersistedQuery(t, h, `{"documentId":"villain"}`)
+	want = `{"errors":[{"message":"unknown documentId \"villain\"","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}]}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+
+	got = servePersistedQuery(t, h, `{"query":"{ hero { id } }"}`)
+	want = `{"errors":[{"message":"query is not a trusted document","extensions":{"code":"UNTRUSTED_DOCUMENT"}}]}`
+	if got != want {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", want, got)
+	}
+}
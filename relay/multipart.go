@@ -0,0 +1,46 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	graphql "github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+)
+
+// serveIncremental executes the request with graphql.Schema.ExecIncremental. Results which were not
+// split into several payloads are written as plain JSON, others as "multipart/mixed" response
+// following the incremental delivery over HTTP proposal, see
+// https://github.com/graphql/graphql-over-http/blob/main/rfcs/IncrementalDelivery.md.
+func (h *Handler) serveIncremental(w http.ResponseWriter, r *http.Request, params *params) {
+	ctx := r.Context()
+	responses := h.Schema.ExecIncremental(ctx, params.Query, params.OperationName, params.Variables)
+
+	first, ok := <-responses
+	if !ok {
+		return // client disconnected
+	}
+	if first.HasNext == nil {
+		writeJSON(w, first)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", `multipart/mixed; boundary="-"`)
+	w.WriteHeader(http.StatusOK)
+
+	for resp := first; resp != nil; resp = <-responses {
+		data, err := json.Marshal(resp)
+		if err != nil {
+			data, _ = json.Marshal(&graphql.Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}, HasNext: resp.HasNext})
+		}
+		fmt.Fprintf(w, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n%s", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	if ctx.Err() == nil {
+		fmt.Fprint(w, "\r\n-----\r\n")
+	}
+}
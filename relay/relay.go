@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	graphql "github.com/sevlyar/graphql-go"
+	qerrors "github.com/sevlyar/graphql-go/errors"
 )
 
 func MarshalID(kind string, spec interface{}) graphql.ID {
@@ -43,22 +44,73 @@ func UnmarshalSpec(id graphql.ID, v interface{}) error {
 	return json.Unmarshal([]byte(s[i+1:]), v)
 }
 
+// params are the parameters of a GraphQL request as sent by clients.
+type params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	DocumentID    string                 `json:"documentId"`
+	Extensions    struct {
+		PersistedQuery *persistedQuery `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// resolveDocumentID replaces the query of the request with the trusted document it refers to, see
+// graphql.TrustedDocuments.
+func (p *params) resolveDocumentID(s *graphql.Schema) *qerrors.QueryError {
+	if p.DocumentID == "" {
+		return nil
+	}
+	q, ok := s.TrustedDocument(p.DocumentID)
+	if !ok {
+		err := qerrors.Errorf("unknown documentId %q", p.DocumentID)
+		err.Extensions = map[string]interface{}{"code": "PERSISTED_QUERY_NOT_FOUND"}
+		return err
+	}
+	p.Query = q
+	return nil
+}
+
+// Handler serves GraphQL requests sent as JSON with POST. If the client accepts "multipart/mixed",
+// then @defer and @stream are honoured and every payload is sent as a separate part.
 type Handler struct {
 	Schema *graphql.Schema
+
+	// PersistedQueries enables Automatic Persisted Queries: a request may send the SHA-256 hash
+	// of the query in "extensions.persistedQuery.sha256Hash" instead of the query itself. Unknown
+	// hashes are answered with a "PersistedQueryNotFound" error, after which the client sends
+	// hash and query together to register the query. Use NewInMemoryPersistedQueryStore unless
+	// the queries should be shared between several servers. If nil, persisted queries are not
+	// supported.
+	PersistedQueries PersistedQueryStore
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var params struct {
-		Query         string                 `json:"query"`
-		OperationName string                 `json:"operationName"`
-		Variables     map[string]interface{} `json:"variables"`
-	}
+	var params params
 	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if err := params.resolveDocumentID(h.Schema); err != nil {
+		writeJSON(w, &graphql.Response{Errors: []*qerrors.QueryError{err}})
+		return
+	}
+	if err := h.resolvePersistedQuery(r.Context(), &params); err != nil {
+		writeJSON(w, &graphql.Response{Errors: []*qerrors.QueryError{err}})
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "multipart/mixed") {
+		h.serveIncremental(w, r, &params)
+		return
+	}
+
 	response := h.Schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+	writeJSON(w, response)
+}
+
+func writeJSON(w http.ResponseWriter, response *graphql.Response) {
 	responseJSON, err := json.Marshal(response)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
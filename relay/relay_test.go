@@ -34,3 +34,41 @@ func TestServeHTTP(t *testing.T) {
 		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", expectedResponse, actualResponse)
 	}
 }
+
+func TestServeHTTPMultipart(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/some/path/here", strings.NewReader(`{"query":"{ hero { name ... @defer { id } } }"}`))
+	r.Header.Set("Accept", "multipart/mixed")
+	h := relay.Handler{Schema: starwarsSchema}
+
+	h.ServeHTTP(w, r)
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType != `multipart/mixed; boundary="-"` {
+		t.Fatalf("Invalid content-type. Expected [multipart/mixed; boundary=\"-\"], but instead got [%s]", contentType)
+	}
+
+	expectedResponse := "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" + `{"data":{"hero":{"name":"R2-D2"}},"hasNext":true}` +
+		"\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" + `{"data":{"id":"2001"},"path":["hero"],"hasNext":false}` +
+		"\r\n-----\r\n"
+	actualResponse := w.Body.String()
+	if expectedResponse != actualResponse {
+		t.Fatalf("Invalid response. Expected [%q], but instead got [%q]", expectedResponse, actualResponse)
+	}
+}
+
+func TestServeHTTPMultipartWithoutDefer(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/some/path/here", strings.NewReader(`{"query":"{ hero { name } }"}`))
+	r.Header.Set("Accept", "multipart/mixed, application/json")
+	h := relay.Handler{Schema: starwarsSchema}
+
+	h.ServeHTTP(w, r)
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("Invalid content-type. Expected [application/json], but instead got [%s]", contentType)
+	}
+	if actualResponse := w.Body.String(); actualResponse != `{"data":{"hero":{"name":"R2-D2"}}}` {
+		t.Fatalf("Invalid response, got [%s]", actualResponse)
+	}
+}
@@ -0,0 +1,113 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	graphql "github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+)
+
+// SSEHandler serves queries, mutations and subscriptions as Server-Sent Events, following the
+// "distinct connections mode" of the graphql-sse protocol, see
+// https://github.com/enisdenjo/graphql-sse/blob/master/PROTOCOL.md.
+//
+// Each request runs a single operation. Every Response is sent as a "next" event and the stream
+// ends with a "complete" event. Events carry no IDs, so a reconnecting client simply starts the
+// operation anew; the Last-Event-ID header is ignored. The operation is cancelled as soon as the
+// client disconnects.
+type SSEHandler struct {
+	Schema *graphql.Schema
+
+	// KeepAlive is the interval in which comments are sent to keep idle connections open. The
+	// default is 12 seconds.
+	KeepAlive time.Duration
+}
+
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var params params
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		params.Query = q.Get("query")
+		params.OperationName = q.Get("operationName")
+		params.DocumentID = q.Get("documentId")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &params.Variables); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := params.resolveDocumentID(h.Schema); err != nil {
+		http.Error(w, err.Message, http.StatusNotFound)
+		return
+	}
+
+	ctx := r.Context()
+	responses, err := h.Schema.Subscribe(ctx, params.Query, params.OperationName, params.Variables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := h.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = 12 * time.Second
+	}
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				if ctx.Err() == nil {
+					fmt.Fprint(w, "event: complete\ndata:\n\n")
+					flusher.Flush()
+				}
+				return
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				data, _ = json.Marshal(&graphql.Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}})
+			}
+			fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+			flusher.Flush()
+
+		case <-ticker.C:
+			fmt.Fprint(w, ":\n\n")
+			flusher.Flush()
+
+		case <-ctx.Done():
+			for range responses {
+				// the executor stops as soon as it notices the disconnect
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package relay_test
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go/relay"
+)
+
+func TestSSESubscription(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/graphql/stream", strings.NewReader(`{"query":"subscription { countdown(from: 1) }"}`))
+	r.Header.Set("Accept", "text/event-stream")
+	h := relay.SSEHandler{Schema: countdownSchema}
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d.", w.Code)
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType != "text/event-stream" {
+		t.Fatalf("Invalid content-type. Expected [text/event-stream], but instead got [%s]", contentType)
+	}
+
+	expectedResponse := "event: next\ndata: {\"data\":{\"countdown\":1}}\n\n" +
+		"event: next\ndata: {\"data\":{\"countdown\":0}}\n\n" +
+		"event: complete\ndata:\n\n"
+	actualResponse := w.Body.String()
+	if expectedResponse != actualResponse {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", expectedResponse, actualResponse)
+	}
+}
+
+func TestSSEQueryViaGet(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/graphql/stream?query="+url.QueryEscape("{ hello }"), nil)
+	h := relay.SSEHandler{Schema: countdownSchema}
+
+	h.ServeHTTP(w, r)
+
+	expectedResponse := "event: next\ndata: {\"data\":{\"hello\":\"Hello world!\"}}\n\n" +
+		"event: complete\ndata:\n\n"
+	actualResponse := w.Body.String()
+	if expectedResponse != actualResponse {
+		t.Fatalf("Invalid response. Expected [%s], but instead got [%s]", expectedResponse, actualResponse)
+	}
+}
@@ -0,0 +1,301 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	graphql "github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+)
+
+// The WebSocket subprotocol spoken by WebSocketHandler, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+const graphqlTransportWS = "graphql-transport-ws"
+
+// Message types of the graphql-transport-ws protocol.
+const (
+	msgConnectionInit = "connection_init"
+	msgConnectionAck  = "connection_ack"
+	msgPing           = "ping"
+	msgPong           = "pong"
+	msgSubscribe      = "subscribe"
+	msgNext           = "next"
+	msgError          = "error"
+	msgComplete       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol.
+const (
+	closeBadRequest          = 4400
+	closeUnauthorized        = 4401
+	closeForbidden           = 4403
+	closeSubprotocol         = 4406
+	closeInitTimeout         = 4408
+	closeSubscriberExists    = 4409
+	closeTooManyInitRequests = 4429
+)
+
+// WebSocketHandler serves queries, mutations and subscriptions over WebSocket connections using
+// the graphql-transport-ws protocol. Every operation is run through Schema.Subscribe, so queries
+// and mutations produce a single "next" message followed by "complete".
+type WebSocketHandler struct {
+	Schema *graphql.Schema
+
+	// InitTimeout is the time a client has to send "connection_init" after the connection was
+	// opened. The default is 3 seconds.
+	InitTimeout time.Duration
+
+	// KeepAlive is the interval in which the server sends "ping" messages. Zero disables them.
+	KeepAlive time.Duration
+
+	// OnInit is called with the payload of "connection_init". It may return a derived context which
+	// is then used for all operations of the connection, e.g. to carry authentication data.
+	// Returning an error rejects the connection.
+	OnInit func(ctx context.Context, payload map[string]interface{}) (context.Context, error)
+
+	// CheckOrigin is passed to the WebSocket upgrader. If nil, only same origin requests are
+	// accepted.
+	CheckOrigin func(r *http.Request) bool
+}
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{graphqlTransportWS},
+		CheckOrigin:  h.CheckOrigin,
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader already replied with an HTTP error
+	}
+
+	c := &wsConn{
+		h:    h,
+		conn: conn,
+		ops:  make(map[string]*wsOperation),
+	}
+	if conn.Subprotocol() != graphqlTransportWS {
+		c.close(closeSubprotocol, "Subprotocol not acceptable")
+		return
+	}
+	c.serve(r.Context())
+}
+
+type wsConn struct {
+	h    *WebSocketHandler
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	opsMu sync.Mutex
+	ops   map[string]*wsOperation
+}
+
+type wsOperation struct {
+	cancel context.CancelFunc
+}
+
+func (c *wsConn) serve(ctx context.Context) {
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops all running operations
+	defer c.conn.Close()
+
+	initTimeout := c.h.InitTimeout
+	if initTimeout == 0 {
+		initTimeout = 3 * time.Second
+	}
+	initDone := make(chan struct{})
+	go func() {
+		select {
+		case <-initDone:
+		case <-connCtx.Done():
+		case <-time.After(initTimeout):
+			c.close(closeInitTimeout, "Connection initialisation timeout")
+		}
+	}()
+
+	if c.h.KeepAlive > 0 {
+		go func() {
+			ticker := time.NewTicker(c.h.KeepAlive)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					c.write(&wsMessage{Type: msgPing})
+				case <-connCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// operations run with the context returned by OnInit, the goroutines above keep connCtx
+	opCtx := connCtx
+	initReceived := false
+	acked := false
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.close(closeBadRequest, "Invalid message received")
+			return
+		}
+
+		switch msg.Type {
+		case msgConnectionInit:
+			if initReceived {
+				c.close(closeTooManyInitRequests, "Too many initialisation requests")
+				return
+			}
+			initReceived = true
+			close(initDone)
+
+			if c.h.OnInit != nil {
+				var payload map[string]interface{}
+				if len(msg.Payload) != 0 {
+					if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+						c.close(closeBadRequest, "Invalid message received")
+						return
+					}
+				}
+				initCtx, err := c.h.OnInit(connCtx, payload)
+				if err != nil {
+					c.close(closeForbidden, "Forbidden")
+					return
+				}
+				opCtx = initCtx
+			}
+			acked = true
+			c.write(&wsMessage{Type: msgConnectionAck})
+
+		case msgPing:
+			c.write(&wsMessage{Type: msgPong})
+
+		case msgPong:
+			// nothing to do
+
+		case msgSubscribe:
+			if !acked {
+				c.close(closeUnauthorized, "Unauthorized")
+				return
+			}
+			var p params
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &p) != nil {
+				c.close(closeBadRequest, "Invalid message received")
+				return
+			}
+			if !c.start(opCtx, msg.ID, &p) {
+				c.close(closeSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+				return
+			}
+
+		case msgComplete:
+			c.stop(msg.ID)
+
+		default:
+			c.close(closeBadRequest, "Invalid message received")
+			return
+		}
+	}
+}
+
+// start runs the operation in its own goroutine. It returns false if an operation with the same ID
+// is already running.
+func (c *wsConn) start(ctx context.Context, id string, p *params) bool {
+	c.opsMu.Lock()
+	defer c.opsMu.Unlock()
+	if _, ok := c.ops[id]; ok {
+		return false
+	}
+	opCtx, cancel := context.WithCancel(ctx)
+	op := &wsOperation{cancel: cancel}
+	c.ops[id] = op
+
+	go func() {
+		defer c.remove(id, op)
+
+		if err := p.resolveDocumentID(c.h.Schema); err != nil {
+			c.writePayload(id, msgError, []*errors.QueryError{err})
+			return
+		}
+
+		responses, err := c.h.Schema.Subscribe(opCtx, p.Query, p.OperationName, p.Variables)
+		if err != nil {
+			c.writePayload(id, msgError, []*errors.QueryError{errors.Errorf("%s", err)})
+			return
+		}
+
+		first := true
+		for resp := range responses {
+			if first && resp.Data == nil && len(resp.Errors) != 0 {
+				// the operation could not be started, which the protocol reports without "complete"
+				c.writePayload(id, msgError, resp.Errors)
+				return
+			}
+			first = false
+			c.writePayload(id, msgNext, resp)
+		}
+
+		if opCtx.Err() == nil {
+			c.write(&wsMessage{ID: id, Type: msgComplete})
+		}
+	}()
+	return true
+}
+
+// stop cancels the operation on request of the client.
+func (c *wsConn) stop(id string) {
+	c.opsMu.Lock()
+	defer c.opsMu.Unlock()
+	if op, ok := c.ops[id]; ok {
+		op.cancel()
+		delete(c.ops, id)
+	}
+}
+
+// remove forgets a finished operation unless the client already reused its ID.
+func (c *wsConn) remove(id string, op *wsOperation) {
+	c.opsMu.Lock()
+	defer c.opsMu.Unlock()
+	op.cancel()
+	if c.ops[id] == op {
+		delete(c.ops, id)
+	}
+}
+
+func (c *wsConn) writePayload(id string, typ string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		data, _ = json.Marshal([]*errors.QueryError{errors.Errorf("%s", err)})
+		typ = msgError
+	}
+	c.write(&wsMessage{ID: id, Type: typ, Payload: data})
+}
+
+// write sends a message. Errors are ignored since a broken connection is noticed by the read loop.
+func (c *wsConn) write(msg *wsMessage) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteJSON(msg)
+}
+
+func (c *wsConn) close(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	c.conn.Close()
+}
@@ -0,0 +1,193 @@
+package relay_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/relay"
+)
+
+var countdownSchema = graphql.MustParseSchema(`
+	schema {
+		query: Query
+		subscription: Subscription
+	}
+
+	type Query {
+		hello: String!
+	}
+
+	type Subscription {
+		countdown(from: Int!): Int!
+	}
+`, &countdownResolver{})
+
+type countdownResolver struct{}
+
+func (r *countdownResolver) Hello() string {
+	return "Hello world!"
+}
+
+func (r *countdownResolver) Countdown(ctx context.Context, args struct{ From int32 }) <-chan int32 {
+	c := make(chan int32)
+	go func() {
+		defer close(c)
+		for i := args.From; i >= 0; i-- {
+			select {
+			case c <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+func dialWebSocket(t *testing.T, h *relay.WebSocketHandler) *websocket.Conn {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	d := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, _, err := d.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func sendMessage(t *testing.T, conn *websocket.Conn, msg string) {
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectMessage(t *testing.T, conn *websocket.Conn, want string) {
+	_, got, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, got, []byte(want)) {
+		t.Fatalf("got message %s, want %s", got, want)
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatal(err)
+	}
+	fa, _ := json.Marshal(va)
+	fb, _ := json.Marshal(vb)
+	return string(fa) == string(fb)
+}
+
+func TestWebSocketSubscription(t *testing.T) {
+	conn := dialWebSocket(t, &relay.WebSocketHandler{Schema: countdownSchema})
+
+	sendMessage(t, conn, `{"type":"connection_init"}`)
+	expectMessage(t, conn, `{"type":"connection_ack"}`)
+
+	sendMessage(t, conn, `{"type":"ping"}`)
+	expectMessage(t, conn, `{"type":"pong"}`)
+
+	sendMessage(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { countdown(from: 2) }"}}`)
+	expectMessage(t, conn, `{"id":"1","type":"next","payload":{"data":{"countdown":2}}}`)
+	expectMessage(t, conn, `{"id":"1","type":"next","payload":{"data":{"countdown":1}}}`)
+	expectMessage(t, conn, `{"id":"1","type":"next","payload":{"data":{"countdown":0}}}`)
+	expectMessage(t, conn, `{"id":"1","type":"complete"}`)
+
+	sendMessage(t, conn, `{"id":"2","type":"subscribe","payload":{"query":"{ hello }"}}`)
+	expectMessage(t, conn, `{"id":"2","type":"next","payload":{"data":{"hello":"Hello world!"}}}`)
+	expectMessage(t, conn, `{"id":"2","type":"complete"}`)
+}
+
+func TestWebSocketValidationError(t *testing.T) {
+	conn := dialWebSocket(t, &relay.WebSocketHandler{Schema: countdownSchema})
+
+	sendMessage(t, conn, `{"type":"connection_init"}`)
+	expectMessage(t, conn, `{"type":"connection_ack"}`)
+
+	sendMessage(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"{ unknown }"}}`)
+	expectMessage(t, conn, `{"id":"1","type":"error","payload":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`)
+}
+
+func TestWebSocketUnauthorized(t *testing.T) {
+	conn := dialWebSocket(t, &relay.WebSocketHandler{Schema: countdownSchema})
+
+	sendMessage(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"{ hello }"}}`)
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, 4401) {
+		t.Fatalf("expected close code 4401, got %v", err)
+	}
+}
+
+func TestWebSocketInitTimeout(t *testing.T) {
+	conn := dialWebSocket(t, &relay.WebSocketHandler{Schema: countdownSchema, InitTimeout: 10 * time.Millisecond})
+
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, 4408) {
+		t.Fatalf("expected close code 4408, got %v", err)
+	}
+}
+
+type authKey struct{}
+
+func TestWebSocketInitKeepAlive(t *testing.T) {
+	conn := dialWebSocket(t, &relay.WebSocketHandler{
+		Schema:      countdownSchema,
+		InitTimeout: time.Second,
+		KeepAlive:   time.Millisecond,
+		OnInit: func(ctx context.Context, payload map[string]interface{}) (context.Context, error) {
+			return context.WithValue(ctx, authKey{}, payload["token"]), nil
+		},
+	})
+
+	// pings may arrive between any two messages
+	pings := 0
+	expect := func(want string) {
+		for {
+			_, got, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if jsonEqual(t, got, []byte(`{"type":"ping"}`)) {
+				pings++
+				continue
+			}
+			if !jsonEqual(t, got, []byte(want)) {
+				t.Fatalf("got message %s, want %s", got, want)
+			}
+			return
+		}
+	}
+
+	sendMessage(t, conn, `{"type":"connection_init","payload":{"token":"secret"}}`)
+	expect(`{"type":"connection_ack"}`)
+
+	sendMessage(t, conn, `{"id":"1","type":"subscribe","payload":{"query":"subscription { countdown(from: 1) }"}}`)
+	expect(`{"id":"1","type":"next","payload":{"data":{"countdown":1}}}`)
+	expect(`{"id":"1","type":"next","payload":{"data":{"countdown":0}}}`)
+	expect(`{"id":"1","type":"complete"}`)
+
+	for pings == 0 {
+		_, got, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, got, []byte(`{"type":"ping"}`)) {
+			t.Fatalf("got message %s, want ping", got)
+		}
+		pings++
+	}
+}
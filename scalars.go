@@ -0,0 +1,56 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/sevlyar/graphql-go/internal/exec/packer"
+	"github.com/sevlyar/graphql-go/internal/schema"
+)
+
+// ScalarCodec converts the values of a custom scalar. It allows resolvers to use any Go type for
+// the scalar, including types of other packages which can not implement UnmarshalGraphQL.
+type ScalarCodec struct {
+	// ParseValue converts an input value given as variable, as decoded from JSON, into the Go value
+	// passed to resolvers. It is required.
+	ParseValue func(value interface{}) (interface{}, error)
+
+	// ParseLiteral converts an input value written in the query or as default value in the schema.
+	// The value is evaluated like for ParseValue, text is the literal as written, e.g. "12.50" or
+	// "\"abc\"", which allows to keep the precision of numbers. ParseValue is used if it is nil.
+	ParseLiteral func(value interface{}, text string) (interface{}, error)
+
+	// Serialize converts a result of a resolver into a value encoded with json.Marshal. An error
+	// is reported as error of the field, which then resolves to null. The result is marshaled as
+	// it is if Serialize is nil.
+	Serialize func(value interface{}) (interface{}, error)
+}
+
+// CustomScalar registers the codec of the custom scalar with the given name. The scalar has to be
+// declared in the schema. Arguments of the scalar are converted with the codec into the type of
+// the resolver's argument, results are serialized with it.
+func CustomScalar(name string, codec ScalarCodec) SchemaOpt {
+	return func(s *Schema) {
+		if s.scalarCodecs == nil {
+			s.scalarCodecs = make(packer.ScalarCodecs)
+		}
+		c := packer.ScalarCodec(codec)
+		s.scalarCodecs[name] = &c
+	}
+}
+
+// checkScalarCodecs checks that the codecs belong to custom scalars of the schema.
+func (s *Schema) checkScalarCodecs() error {
+	for name, codec := range s.scalarCodecs {
+		t, ok := s.schema.Types[name]
+		if !ok {
+			return fmt.Errorf("scalar %q of codec is not declared in the schema", name)
+		}
+		if _, ok := t.(*schema.Scalar); !ok || schema.Meta.Types[name] == t {
+			return fmt.Errorf("codec can not be registered for %s %q, only for custom scalars", t.Kind(), name)
+		}
+		if codec.ParseValue == nil {
+			return fmt.Errorf("codec of scalar %q has no ParseValue function", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package scalars
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Int64 is a signed 64-bit integer. It is returned as number if it is exact as float64, i.e. its
+// absolute value is at most 2^53 - 1, otherwise as string.
+type Int64 int64
+
+func (Int64) ImplementsGraphQLType(name string) bool {
+	return name == "Int64"
+}
+
+func (i *Int64) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		*i = Int64(input)
+	case int:
+		*i = Int64(input)
+	case int64:
+		*i = Int64(input)
+	case float64:
+		n, err := exactInteger(input)
+		if err != nil {
+			return err
+		}
+		*i = Int64(n)
+	case string:
+		n, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Int64 %q", input)
+		}
+		*i = Int64(n)
+	default:
+		return wrongType("Int64", input)
+	}
+	return nil
+}
+
+func (i Int64) MarshalJSON() ([]byte, error) {
+	if i > maxSafeInteger || i < -maxSafeInteger {
+		return strconv.AppendQuote(nil, strconv.FormatInt(int64(i), 10)), nil
+	}
+	return strconv.AppendInt(nil, int64(i), 10), nil
+}
+
+// BigInt is an integer of arbitrary size. It is returned as string.
+type BigInt struct {
+	*big.Int
+}
+
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		b.Int = big.NewInt(int64(input))
+	case int:
+		b.Int = big.NewInt(int64(input))
+	case float64:
+		n, err := exactInteger(input)
+		if err != nil {
+			return err
+		}
+		b.Int = big.NewInt(n)
+	case string:
+		n, ok := new(big.Int).SetString(input, 10)
+		if !ok {
+			return fmt.Errorf("invalid BigInt %q", input)
+		}
+		b.Int = n
+	default:
+		return wrongType("BigInt", input)
+	}
+	return nil
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	if b.Int == nil {
+		return nil, fmt.Errorf("BigInt without value")
+	}
+	return strconv.AppendQuote(nil, b.Int.String()), nil
+}
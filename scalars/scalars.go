@@ -0,0 +1,74 @@
+// Package scalars provides commonly used custom scalars. Each type validates its input strictly,
+// formats its output deterministically and is declared in Schema with a @specifiedBy URL.
+//
+// The types can be used for arguments and results of resolvers once the scalars are declared:
+//
+//	schema := graphql.MustParseSchema(scalars.Schema+mySchema, &myResolver{})
+package scalars
+
+import (
+	"fmt"
+	"math"
+)
+
+// Schema declares all scalars of this package. Scalars which are not used by the rest of the
+// schema do no harm.
+const Schema = `
+	"""
+	A signed 64-bit integer. It is written as number, which is exact up to 2^53 - 1, or as string of
+	decimal digits for the whole range. Results beyond 2^53 - 1 are always written as string.
+	"""
+	scalar Int64 @specifiedBy(url: "https://pkg.go.dev/github.com/sevlyar/graphql-go/scalars#Int64")
+
+	"""
+	An integer of arbitrary size. It is written as string of decimal digits, as input also as number
+	up to 2^53 - 1.
+	"""
+	scalar BigInt @specifiedBy(url: "https://pkg.go.dev/github.com/sevlyar/graphql-go/scalars#BigInt")
+
+	"""A calendar date like "2006-01-02", the full-date of RFC 3339."""
+	scalar Date @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3339#section-5.6")
+
+	"""
+	An instant in time like "2006-01-02T15:04:05Z", the date-time of RFC 3339. It is always returned
+	in UTC.
+	"""
+	scalar DateTime @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3339#section-5.6")
+
+	"""
+	A duration like "PT1H30M", as defined by ISO 8601 without years, months and weeks. A day is 24
+	hours. It is returned without days.
+	"""
+	scalar Duration @specifiedBy(url: "https://en.wikipedia.org/wiki/ISO_8601#Durations")
+
+	"""Any JSON value."""
+	scalar JSON @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc8259")
+
+	"""An absolute URL."""
+	scalar URL @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3986")
+
+	"""A UUID like "f81d4fae-7dec-11d0-a765-00a0c91e6bf6". It is returned in lower case."""
+	scalar UUID @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc4122")
+
+	"""An e-mail address like "gopher@example.com", without display name."""
+	scalar Email @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1")
+`
+
+// maxSafeInteger is the largest integer n for which n and n+1 are exact as float64.
+const maxSafeInteger = 1<<53 - 1
+
+// exactInteger converts a number of the input into an integer. Numbers are decoded as float64, so
+// larger integers may already have lost precision and are rejected.
+func exactInteger(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%v is not an integer", f)
+	}
+	if math.Abs(f) > maxSafeInteger {
+		return 0, fmt.Errorf("%v is not exact as number, it has to be written as string", f)
+	}
+	return int64(f), nil
+}
+
+func wrongType(name string, input interface{}) error {
+	return fmt.Errorf("wrong type for %s: %T", name, input)
+}
@@ -0,0 +1,173 @@
+package scalars_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+	"github.com/sevlyar/graphql-go/scalars"
+)
+
+const echoSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		int64(v: Int64!): Int64!
+		bigInt(v: BigInt!): BigInt!
+		date(v: Date!): Date!
+		dateTime(v: DateTime!): DateTime!
+		duration(v: Duration!): Duration!
+		json(v: JSON!): JSON!
+		url(v: URL!): URL!
+		uuid(v: UUID!): UUID!
+		email(v: Email!): Email!
+	}
+`
+
+type echoResolver struct{}
+
+func (r *echoResolver) Int64(args struct{ V scalars.Int64 }) scalars.Int64          { return args.V }
+func (r *echoResolver) BigInt(args struct{ V scalars.BigInt }) scalars.BigInt       { return args.V }
+func (r *echoResolver) Date(args struct{ V scalars.Date }) scalars.Date             { return args.V }
+func (r *echoResolver) DateTime(args struct{ V scalars.DateTime }) scalars.DateTime { return args.V }
+func (r *echoResolver) Duration(args struct{ V scalars.Duration }) scalars.Duration { return args.V }
+func (r *echoResolver) JSON(args struct{ V scalars.JSON }) scalars.JSON             { return args.V }
+func (r *echoResolver) URL(args struct{ V scalars.URL }) scalars.URL                { return args.V }
+func (r *echoResolver) UUID(args struct{ V scalars.UUID }) scalars.UUID             { return args.V }
+func (r *echoResolver) Email(args struct{ V scalars.Email }) scalars.Email          { return args.V }
+
+func TestScalars(t *testing.T) {
+	s := graphql.MustParseSchema(scalars.Schema+echoSchema, &echoResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				{
+					small: int64(v: -42)
+					large: int64(v: "9223372036854775807")
+					bigInt(v: "-123456789012345678901234567890")
+					bigIntNumber: bigInt(v: 7)
+					date(v: "2017-02-28")
+					dateTime(v: "2017-02-28T10:30:00.500+02:00")
+					duration(v: "P1DT2H3M4.05S")
+					zero: duration(v: "PT0S")
+					negative: duration(v: "-PT90M")
+					json(v: {b: [1, "two", null], a: true})
+					url(v: "https://example.com/a?b=c#d")
+					uuid(v: "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6")
+					email(v: "gopher@example.com")
+				}
+			`,
+			ExpectedResult: `
+				{
+					"small": -42,
+					"large": "9223372036854775807",
+					"bigInt": "-123456789012345678901234567890",
+					"bigIntNumber": "7",
+					"date": "2017-02-28",
+					"dateTime": "2017-02-28T08:30:00.5Z",
+					"duration": "PT26H3M4.05S",
+					"zero": "PT0S",
+					"negative": "-PT1H30M",
+					"json": {"a": true, "b": [1, "two", null]},
+					"url": "https://example.com/a?b=c#d",
+					"uuid": "f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+					"email": "gopher@example.com"
+				}
+			`,
+		},
+		{
+			Schema: s,
+			Query: `
+				{
+					__type(name: "UUID") {
+						specifiedByURL
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"__type": {
+						"specifiedByURL": "https://www.rfc-editor.org/rfc/rfc4122"
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestScalarsInvalidInput(t *testing.T) {
+	s := graphql.MustParseSchema(scalars.Schema+echoSchema, &echoResolver{})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ int64(v: 1.5) }`, `1.5 is not an integer`},
+		{`{ int64(v: 9007199254740993) }`, `it has to be written as string`},
+		{`{ int64(v: "9223372036854775808") }`, `invalid Int64 "9223372036854775808"`},
+		{`{ bigInt(v: "12a") }`, `invalid BigInt "12a"`},
+		{`{ date(v: "2017-2-28") }`, `invalid Date "2017-2-28"`},
+		{`{ date(v: "2017-02-30") }`, `invalid Date "2017-02-30"`},
+		{`{ dateTime(v: "2017-02-28 10:30:00") }`, `invalid DateTime "2017-02-28 10:30:00"`},
+		{`{ duration(v: "P1M") }`, `invalid Duration "P1M"`},
+		{`{ duration(v: "PT") }`, `invalid Duration "PT"`},
+		{`{ duration(v: "P1DT") }`, `invalid Duration "P1DT"`},
+		{`{ duration(v: "PT9999999999H") }`, `Duration "PT9999999999H" is out of range`},
+		{`{ url(v: "/relative/path") }`, `invalid URL "/relative/path"`},
+		{`{ uuid(v: "f81d4fae7dec11d0a76500a0c91e6bf6") }`, `invalid UUID "f81d4fae7dec11d0a76500a0c91e6bf6"`},
+		{`{ uuid(v: "f81d4fae-7dec-11d0-a765-00a0c91e6bfg") }`, `invalid UUID`},
+		{`{ email(v: "Gopher <gopher@example.com>") }`, `invalid Email "Gopher <gopher@example.com>"`},
+		{`{ email(v: "gopher") }`, `invalid Email "gopher"`},
+		{`{ date(v: 20170228) }`, `wrong type for Date: float64`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result := s.Exec(context.Background(), tt.query, "", nil)
+			if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, tt.want) {
+				t.Errorf("got errors %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalarsOutput(t *testing.T) {
+	tests := []struct {
+		value json.Marshaler
+		want  string
+	}{
+		{scalars.Int64(-1 << 63), `"-9223372036854775808"`},
+		{scalars.Int64(1<<53 - 1), `9007199254740991`},
+		{scalars.Int64(-1<<53 + 1), `-9007199254740991`},
+		{scalars.Int64(1 << 53), `"9007199254740992"`},
+		{scalars.BigInt{Int: new(big.Int).Lsh(big.NewInt(1), 100)}, `"1267650600228229401496703205376"`},
+		{scalars.Date{Time: time.Date(2017, 2, 28, 23, 59, 0, 0, time.FixedZone("", -3600))}, `"2017-02-28"`},
+		{scalars.DateTime{Time: time.Date(2017, 2, 28, 23, 59, 0, 0, time.FixedZone("", -3600))}, `"2017-03-01T00:59:00Z"`},
+		{scalars.Duration{Duration: 1500 * time.Millisecond}, `"PT1.5S"`},
+		{scalars.Duration{Duration: -1 << 63}, `"-PT2562047H47M16.854775808S"`},
+		{scalars.JSON(nil), `null`},
+		{scalars.UUID{0xf8, 0x1d, 0x4f, 0xae}, `"f81d4fae-0000-0000-0000-000000000000"`},
+	}
+	for _, tt := range tests {
+		got, err := tt.value.MarshalJSON()
+		if err != nil {
+			t.Errorf("%#v: %s", tt.value, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%#v: got %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := (scalars.JSON("{")).MarshalJSON(); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
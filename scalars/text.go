@@ -0,0 +1,129 @@
+package scalars
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// JSON is any JSON value, as input it is encoded from the given value.
+type JSON json.RawMessage
+
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %s", err)
+	}
+	*j = data
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	if !json.Valid(j) {
+		return nil, fmt.Errorf("invalid JSON %q", string(j))
+	}
+	return j, nil
+}
+
+// URL is an absolute URL.
+type URL struct {
+	url.URL
+}
+
+func (URL) ImplementsGraphQLType(name string) bool {
+	return name == "URL"
+}
+
+func (u *URL) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("URL", input)
+	}
+	parsed, err := url.Parse(s)
+	if err != nil || !parsed.IsAbs() || strings.ContainsAny(s, " \t\r\n") {
+		return fmt.Errorf("invalid URL %q", s)
+	}
+	u.URL = *parsed
+	return nil
+}
+
+func (u URL) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, u.String()), nil
+}
+
+// UUID is a universally unique identifier.
+type UUID [16]byte
+
+func (UUID) ImplementsGraphQLType(name string) bool {
+	return name == "UUID"
+}
+
+func (u *UUID) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("UUID", input)
+	}
+	parsed, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ParseUUID parses a UUID in the canonical form of 36 characters.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+func (u UUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, u.String()), nil
+}
+
+// Email is an e-mail address without display name.
+type Email string
+
+func (Email) ImplementsGraphQLType(name string) bool {
+	return name == "Email"
+}
+
+func (e *Email) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("Email", input)
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s || len(s) > 254 {
+		return fmt.Errorf("invalid Email %q", s)
+	}
+	*e = Email(s)
+	return nil
+}
+
+func (e Email) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, string(e)), nil
+}
@@ -0,0 +1,144 @@
+package scalars
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date. Only year, month and day of Time are used.
+type Date struct {
+	time.Time
+}
+
+func (Date) ImplementsGraphQLType(name string) bool {
+	return name == "Date"
+}
+
+func (d *Date) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("Date", input)
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid Date %q", s)
+	}
+	d.Time = t
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, d.Format(dateLayout)), nil
+}
+
+// DateTime is an instant in time. It is returned in UTC with the fraction of seconds as needed.
+type DateTime struct {
+	time.Time
+}
+
+func (DateTime) ImplementsGraphQLType(name string) bool {
+	return name == "DateTime"
+}
+
+func (d *DateTime) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("DateTime", input)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return fmt.Errorf("invalid DateTime %q", s)
+	}
+	d.Time = t
+	return nil
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, d.UTC().Format(time.RFC3339Nano)), nil
+}
+
+// Duration is a duration in ISO 8601 format.
+type Duration struct {
+	time.Duration
+}
+
+func (Duration) ImplementsGraphQLType(name string) bool {
+	return name == "Duration"
+}
+
+var durationPattern = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:\.(\d{1,9}))?S)?)?$`)
+
+func (d *Duration) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return wrongType("Duration", input)
+	}
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "-P" || strings.HasSuffix(s, "T") {
+		return fmt.Errorf("invalid Duration %q", s)
+	}
+
+	var total time.Duration
+	for _, part := range []struct {
+		digits string
+		unit   time.Duration
+	}{{m[2], 24 * time.Hour}, {m[3], time.Hour}, {m[4], time.Minute}, {m[5], time.Second}} {
+		if part.digits == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(part.digits, 10, 64)
+		if err != nil || n > int64(maxDuration/part.unit) || time.Duration(n)*part.unit > maxDuration-total {
+			return fmt.Errorf("Duration %q is out of range", s)
+		}
+		total += time.Duration(n) * part.unit
+	}
+	if m[6] != "" {
+		nanos, _ := strconv.ParseInt((m[6] + "00000000")[:9], 10, 64)
+		if time.Duration(nanos) > maxDuration-total {
+			return fmt.Errorf("Duration %q is out of range", s)
+		}
+		total += time.Duration(nanos)
+	}
+	if m[1] == "-" {
+		total = -total
+	}
+	d.Duration = total
+	return nil
+}
+
+const maxDuration = time.Duration(1<<63 - 1)
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	v := uint64(d.Duration)
+	if d.Duration < 0 {
+		b.WriteByte('-')
+		v = -v // two's complement, also correct for the smallest duration
+	}
+	b.WriteString("PT")
+	if v == 0 {
+		b.WriteString("0S")
+	}
+	if h := v / uint64(time.Hour); h != 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		v -= h * uint64(time.Hour)
+	}
+	if m := v / uint64(time.Minute); m != 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		v -= m * uint64(time.Minute)
+	}
+	if v != 0 {
+		s := v / uint64(time.Second)
+		fmt.Fprintf(&b, "%d", s)
+		if nanos := v - s*uint64(time.Second); nanos != 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		b.WriteByte('S')
+	}
+	return strconv.AppendQuote(nil, b.String()), nil
+}
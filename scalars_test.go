@@ -0,0 +1,240 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const scalarsSchema = `
+	schema {
+		query: Query
+		subscription: Subscription
+	}
+
+	scalar IP
+	scalar Money
+
+	type Query {
+		lookup(ip: IP!): IP
+		loopbacks(ips: [IP!] = ["127.0.0.1", "::1"]): [IP!]!
+		price(amount: Money!): String!
+		invalid: IP
+		nested: Nested!
+	}
+
+	type Nested {
+		total: Money!
+	}
+
+	type Subscription {
+		ticks: Money!
+	}
+`
+
+// cents stands for a decimal type of another package.
+type cents int64
+
+var ipCodec = graphql.ScalarCodec{
+	ParseValue: func(value interface{}) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+		return netip.ParseAddr(s)
+	},
+	Serialize: func(value interface{}) (interface{}, error) {
+		addr := value.(netip.Addr)
+		if !addr.IsValid() {
+			return nil, fmt.Errorf("invalid address")
+		}
+		return addr.String(), nil
+	},
+}
+
+var moneyCodec = graphql.ScalarCodec{
+	ParseValue: func(value interface{}) (interface{}, error) {
+		f, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected number, got %T", value)
+		}
+		return cents(math.Round(f * 100)), nil
+	},
+	ParseLiteral: func(value interface{}, text string) (interface{}, error) {
+		units, fraction := text, ""
+		if i := strings.IndexByte(text, '.'); i >= 0 {
+			units, fraction = text[:i], text[i+1:]
+		}
+		if len(fraction) > 2 {
+			return nil, fmt.Errorf("%s has more than two decimals", text)
+		}
+		n, err := strconv.ParseInt(units+(fraction + "00")[:2], 10, 64)
+		return cents(n), err
+	},
+	Serialize: func(value interface{}) (interface{}, error) {
+		c := value.(cents)
+		return fmt.Sprintf("$%d.%02d", c/100, c%100), nil
+	},
+}
+
+type scalarsResolver struct{}
+
+func (r *scalarsResolver) Lookup(args struct{ IP netip.Addr }) *netip.Addr {
+	return &args.IP
+}
+
+func (r *scalarsResolver) Loopbacks(args struct{ IPs []netip.Addr }) []netip.Addr {
+	var loopbacks []netip.Addr
+	for _, ip := range args.IPs {
+		if ip.IsLoopback() {
+			loopbacks = append(loopbacks, ip)
+		}
+	}
+	return loopbacks
+}
+
+func (r *scalarsResolver) Price(args struct{ Amount cents }) string {
+	return fmt.Sprintf("%d cents", args.Amount)
+}
+
+func (r *scalarsResolver) Invalid() *netip.Addr {
+	return &netip.Addr{}
+}
+
+func (r *scalarsResolver) Nested() *scalarsResolver {
+	return r
+}
+
+func (r *scalarsResolver) Total() cents {
+	return 1250
+}
+
+func (r *scalarsResolver) Ticks() <-chan cents {
+	c := make(chan cents, 1)
+	c <- 1250
+	close(c)
+	return c
+}
+
+func TestCustomScalar(t *testing.T) {
+	s := graphql.MustParseSchema(scalarsSchema, &scalarsResolver{},
+		graphql.CustomScalar("IP", ipCodec),
+		graphql.CustomScalar("Money", moneyCodec),
+	)
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				query($ip: IP!) {
+					literal: lookup(ip: "10.0.0.1")
+					variable: lookup(ip: $ip)
+					defaults: loopbacks
+					list: loopbacks(ips: ["10.0.0.1", $ip, "127.0.0.2"])
+					price(amount: 12.5)
+				}
+			`,
+			Variables: map[string]interface{}{"ip": "::1"},
+			ExpectedResult: `
+				{
+					"literal": "10.0.0.1",
+					"variable": "::1",
+					"defaults": ["127.0.0.1", "::1"],
+					"list": ["::1", "127.0.0.2"],
+					"price": "1250 cents"
+				}
+			`,
+		},
+		{
+			Schema: s,
+			Query: `
+				query($amount: Money!) {
+					price(amount: $amount)
+				}
+			`,
+			Variables: map[string]interface{}{"amount": 1.125},
+			ExpectedResult: `
+				{
+					"price": "113 cents"
+				}
+			`,
+		},
+	})
+}
+
+func TestCustomScalarIncremental(t *testing.T) {
+	s := graphql.MustParseSchema(scalarsSchema, &scalarsResolver{},
+		graphql.CustomScalar("IP", ipCodec),
+		graphql.CustomScalar("Money", moneyCodec),
+	)
+
+	got := collectIncremental(t, s, `{ nested { total } ... @defer { later: nested { total } } }`)
+	expectPayloads(t, got,
+		`{"data":{"nested":{"total":"$12.50"}},"hasNext":true}`,
+		`{"data":{"later":{"total":"$12.50"}},"hasNext":false}`,
+	)
+
+	c, err := s.Subscribe(context.Background(), `subscription { ticks }`, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	for resp := range c {
+		if len(resp.Errors) != 0 {
+			t.Fatal(resp.Errors[0])
+		}
+		events = append(events, string(resp.Data))
+	}
+	if len(events) != 1 || events[0] != `{"ticks":"$12.50"}` {
+		t.Errorf("got events %v", events)
+	}
+}
+
+func TestCustomScalarErrors(t *testing.T) {
+	s := graphql.MustParseSchema(scalarsSchema, &scalarsResolver{},
+		graphql.CustomScalar("IP", ipCodec),
+		graphql.CustomScalar("Money", moneyCodec),
+	)
+
+	result := s.Exec(context.Background(), `{ price(amount: 1.005) }`, "", nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "could not parse Money: 1.005 has more than two decimals" {
+		t.Errorf("got errors %v", result.Errors)
+	}
+
+	result = s.Exec(context.Background(), `{ invalid lookup(ip: "10.0.0.2") }`, "", nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "could not serialize IP: invalid address" {
+		t.Fatalf("got errors %v", result.Errors)
+	}
+	if path, _ := json.Marshal(result.Errors[0].Path); string(path) != `["invalid"]` {
+		t.Errorf("got path %s", path)
+	}
+	if string(result.Data) != `{"invalid":null,"lookup":"10.0.0.2"}` {
+		t.Errorf("got data %s", result.Data)
+	}
+
+	tests := []struct {
+		name  string
+		codec string
+		want  string
+	}{
+		{"undeclared", "Missing", `scalar "Missing" of codec is not declared in the schema`},
+		{"builtin", "Int", `codec can not be registered for SCALAR "Int", only for custom scalars`},
+		{"not a scalar", "Query", `codec can not be registered for OBJECT "Query", only for custom scalars`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseSchema(scalarsSchema, nil, graphql.CustomScalar(tt.codec, ipCodec))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+// Package schemadiff compares two versions of a schema and classifies the changes by how they
+// affect existing clients.
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/schema"
+)
+
+// Criticality describes how a change affects existing clients.
+type Criticality int
+
+const (
+	// Safe changes can not break any existing query.
+	Safe Criticality = iota
+	// Dangerous changes keep all existing queries valid, but may change their results, e.g. a
+	// changed default value or a new enum value which a client does not know how to handle.
+	Dangerous
+	// Breaking changes make existing queries invalid or change the guarantees of their results.
+	Breaking
+)
+
+func (c Criticality) String() string {
+	switch c {
+	case Safe:
+		return "SAFE"
+	case Dangerous:
+		return "DANGEROUS"
+	case Breaking:
+		return "BREAKING"
+	default:
+		return fmt.Sprintf("Criticality(%d)", int(c))
+	}
+}
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Criticality Criticality
+	// Path is the schema coordinate of the changed element, e.g. "Query.user.id" for the argument
+	// "id" of the field "user" or "@cache" for a directive.
+	Path    string
+	Message string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s: %s", c.Criticality, c.Path, c.Message)
+}
+
+// Compare parses both schemas and returns the changes from oldSchema to newSchema, sorted by path.
+func Compare(oldSchema, newSchema string) ([]Change, error) {
+	o := schema.New()
+	if err := o.Parse(oldSchema); err != nil {
+		return nil, fmt.Errorf("old schema: %s", err)
+	}
+	n := schema.New()
+	if err := n.Parse(newSchema); err != nil {
+		return nil, fmt.Errorf("new schema: %s", err)
+	}
+
+	d := &differ{}
+	d.schemas(o, n)
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Path < d.changes[j].Path
+	})
+	return d.changes, nil
+}
+
+// HasBreaking reports whether any of the changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Criticality == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(criticality Criticality, path string, format string, a ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Criticality: criticality,
+		Path:        path,
+		Message:     fmt.Sprintf(format, a...),
+	})
+}
+
+func (d *differ) schemas(o, n *schema.Schema) {
+	for _, key := range []string{"query", "mutation", "subscription"} {
+		ot, oOK := o.EntryPoints[key]
+		nt, nOK := n.EntryPoints[key]
+		switch {
+		case oOK && !nOK:
+			d.add(Breaking, "schema", "the %s type %q was removed", key, ot.TypeName())
+		case !oOK && nOK:
+			d.add(Safe, "schema", "the %s type %q was added", key, nt.TypeName())
+		case oOK && nOK && ot.TypeName() != nt.TypeName():
+			d.add(Breaking, "schema", "the %s type changed from %q to %q", key, ot.TypeName(), nt.TypeName())
+		}
+	}
+
+	for _, name := range sortedNames(o.Types, n.Types) {
+		ot, nt := o.Types[name], n.Types[name]
+		if ot == nt {
+			continue // builtin
+		}
+		switch {
+		case nt == nil:
+			d.add(Breaking, name, "type %q was removed", name)
+		case ot == nil:
+			d.add(Safe, name, "type %q was added", name)
+		case ot.Kind() != nt.Kind():
+			d.add(Breaking, name, "type %q changed from %s to %s", name, ot.Kind(), nt.Kind())
+		default:
+			d.namedType(ot, nt)
+		}
+	}
+
+	for _, name := range sortedNames(o.Directives, n.Directives) {
+		od, nd := o.Directives[name], n.Directives[name]
+		if od == nd {
+			continue // builtin
+		}
+		path := "@" + name
+		switch {
+		case nd == nil:
+			d.add(Breaking, path, "directive %q was removed", name)
+		case od == nil:
+			d.add(Safe, path, "directive %q was added", name)
+		default:
+			d.directiveDecl(path, od, nd)
+		}
+	}
+}
+
+func (d *differ) namedType(ot, nt schema.NamedType) {
+	name := ot.TypeName()
+	if ot.Description() != nt.Description() {
+		d.add(Safe, name, "description of type %q changed", name)
+	}
+
+	switch ot := ot.(type) {
+	case *schema.Object:
+		nt := nt.(*schema.Object)
+		d.interfaces(name, ot.Interfaces, nt.Interfaces)
+		d.fields(name, ot.Fields, nt.Fields)
+
+	case *schema.Interface:
+		nt := nt.(*schema.Interface)
+		d.interfaces(name, ot.Interfaces, nt.Interfaces)
+		d.fields(name, ot.Fields, nt.Fields)
+
+	case *schema.Union:
+		nt := nt.(*schema.Union)
+		oldMembers := objectNames(ot.PossibleTypes)
+		newMembers := objectNames(nt.PossibleTypes)
+		for _, m := range oldMembers {
+			if !contains(newMembers, m) {
+				d.add(Breaking, name, "member %q was removed from union %q", m, name)
+			}
+		}
+		for _, m := range newMembers {
+			if !contains(oldMembers, m) {
+				d.add(Dangerous, name, "member %q was added to union %q", m, name)
+			}
+		}
+
+	case *schema.Enum:
+		nt := nt.(*schema.Enum)
+		newValues := make(map[string]*schema.EnumValue)
+		for _, v := range nt.Values {
+			newValues[v.Name] = v
+		}
+		oldValues := make(map[string]*schema.EnumValue)
+		for _, ov := range ot.Values {
+			oldValues[ov.Name] = ov
+			path := name + "." + ov.Name
+			nv, ok := newValues[ov.Name]
+			if !ok {
+				d.add(Breaking, path, "value %q was removed from enum %q", ov.Name, name)
+				continue
+			}
+			if ov.Desc != nv.Desc {
+				d.add(Safe, path, "description of enum value %q changed", path)
+			}
+			d.deprecation(path, "enum value", ov.Directives, nv.Directives)
+		}
+		for _, nv := range nt.Values {
+			if _, ok := oldValues[nv.Name]; !ok {
+				d.add(Dangerous, name+"."+nv.Name, "value %q was added to enum %q", nv.Name, name)
+			}
+		}
+
+	case *schema.InputObject:
+		nt := nt.(*schema.InputObject)
+		for _, ov := range ot.Values {
+			path := name + "." + ov.Name.Name
+			nv := nt.Values.Get(ov.Name.Name)
+			if nv == nil {
+				d.add(Breaking, path, "input field %q was removed", path)
+				continue
+			}
+			d.inputValue(path, "input field", ov, nv)
+		}
+		for _, nv := range nt.Values {
+			if ot.Values.Get(nv.Name.Name) != nil {
+				continue
+			}
+			path := name + "." + nv.Name.Name
+			if isRequired(nv) {
+				d.add(Breaking, path, "required input field %q was added", path)
+			} else {
+				d.add(Dangerous, path, "optional input field %q was added", path)
+			}
+		}
+	}
+}
+
+func (d *differ) interfaces(name string, oi, ni []*schema.Interface) {
+	oldNames := interfaceNames(oi)
+	newNames := interfaceNames(ni)
+	for _, intf := range oldNames {
+		if !contains(newNames, intf) {
+			d.add(Breaking, name, "type %q no longer implements %q", name, intf)
+		}
+	}
+	for _, intf := range newNames {
+		if !contains(oldNames, intf) {
+			d.add(Dangerous, name, "type %q now implements %q", name, intf)
+		}
+	}
+}
+
+func (d *differ) fields(name string, of, nf schema.FieldList) {
+	for _, o := range of {
+		path := name + "." + o.Name
+		n := nf.Get(o.Name)
+		if n == nil {
+			d.add(Breaking, path, "field %q was removed", path)
+			continue
+		}
+		if !isSafeOutputChange(o.Type, n.Type) {
+			d.add(Breaking, path, "field %q changed type from %q to %q", path, o.Type, n.Type)
+		} else if o.Type.String() != n.Type.String() {
+			d.add(Safe, path, "field %q changed type from %q to %q", path, o.Type, n.Type)
+		}
+		if o.Desc != n.Desc {
+			d.add(Safe, path, "description of field %q changed", path)
+		}
+		d.deprecation(path, "field", o.Directives, n.Directives)
+		d.args(path, "field", o.Args, n.Args)
+	}
+	for _, n := range nf {
+		if of.Get(n.Name) == nil {
+			d.add(Safe, name+"."+n.Name, "field %q was added", name+"."+n.Name)
+		}
+	}
+}
+
+func (d *differ) directiveDecl(path string, od, nd *schema.DirectiveDecl) {
+	for _, loc := range od.Locs {
+		if !contains(nd.Locs, loc) {
+			d.add(Breaking, path, "location %s was removed from directive %q", loc, path)
+		}
+	}
+	for _, loc := range nd.Locs {
+		if !contains(od.Locs, loc) {
+			d.add(Safe, path, "location %s was added to directive %q", loc, path)
+		}
+	}
+	if od.Repeatable && !nd.Repeatable {
+		d.add(Breaking, path, "directive %q is no longer repeatable", path)
+	}
+	if !od.Repeatable && nd.Repeatable {
+		d.add(Safe, path, "directive %q is now repeatable", path)
+	}
+	d.args(path, "directive", od.Args, nd.Args)
+}
+
+func (d *differ) args(path string, owner string, oa, na common.InputValueList) {
+	for _, o := range oa {
+		argPath := path + "." + o.Name.Name
+		n := na.Get(o.Name.Name)
+		if n == nil {
+			d.add(Breaking, argPath, "argument %q was removed from %s %q", o.Name.Name, owner, path)
+			continue
+		}
+		d.inputValue(argPath, "argument", o, n)
+	}
+	for _, n := range na {
+		if oa.Get(n.Name.Name) != nil {
+			continue
+		}
+		argPath := path + "." + n.Name.Name
+		if isRequired(n) {
+			d.add(Breaking, argPath, "required argument %q was added to %s %q", n.Name.Name, owner, path)
+		} else {
+			d.add(Dangerous, argPath, "optional argument %q was added to %s %q", n.Name.Name, owner, path)
+		}
+	}
+}
+
+func (d *differ) inputValue(path string, kind string, o, n *common.InputValue) {
+	if !isSafeInputChange(o.Type, n.Type) {
+		d.add(Breaking, path, "%s %q changed type from %q to %q", kind, path, o.Type, n.Type)
+	} else if o.Type.String() != n.Type.String() {
+		d.add(Safe, path, "%s %q changed type from %q to %q", kind, path, o.Type, n.Type)
+	}
+
+	switch {
+	case o.Default != nil && n.Default == nil:
+		d.add(Dangerous, path, "default value %s was removed from %s %q", o.Default, kind, path)
+	case o.Default == nil && n.Default != nil:
+		d.add(Dangerous, path, "default value %s was added to %s %q", n.Default, kind, path)
+	case o.Default != nil && o.Default.String() != n.Default.String():
+		d.add(Dangerous, path, "default value of %s %q changed from %s to %s", kind, path, o.Default, n.Default)
+	}
+
+	if o.Desc != n.Desc {
+		d.add(Safe, path, "description of %s %q changed", kind, path)
+	}
+}
+
+func (d *differ) deprecation(path string, kind string, od, nd common.DirectiveList) {
+	o := od.Get("deprecated") != nil
+	n := nd.Get("deprecated") != nil
+	switch {
+	case !o && n:
+		d.add(Safe, path, "%s %q was deprecated", kind, path)
+	case o && !n:
+		d.add(Safe, path, "%s %q is no longer deprecated", kind, path)
+	}
+}
+
+// isSafeOutputChange reports whether every value of the new type is also a valid value of the old
+// type, so clients reading the value are not affected.
+func isSafeOutputChange(o, n common.Type) bool {
+	switch o := o.(type) {
+	case *common.List:
+		switch n := n.(type) {
+		case *common.List:
+			return isSafeOutputChange(o.OfType, n.OfType)
+		case *common.NonNull:
+			return isSafeOutputChange(o, n.OfType)
+		}
+		return false
+	case *common.NonNull:
+		if n, ok := n.(*common.NonNull); ok {
+			return isSafeOutputChange(o.OfType, n.OfType)
+		}
+		return false
+	case schema.NamedType:
+		switch n := n.(type) {
+		case *common.NonNull:
+			return isSafeOutputChange(o, n.OfType)
+		case schema.NamedType:
+			return o.TypeName() == n.TypeName()
+		}
+		return false
+	}
+	return false
+}
+
+// isSafeInputChange reports whether every value accepted by the old type is also accepted by the
+// new type, so clients sending the value are not affected.
+func isSafeInputChange(o, n common.Type) bool {
+	switch o := o.(type) {
+	case *common.List:
+		if n, ok := n.(*common.List); ok {
+			return isSafeInputChange(o.OfType, n.OfType)
+		}
+		return false
+	case *common.NonNull:
+		if n, ok := n.(*common.NonNull); ok {
+			return isSafeInputChange(o.OfType, n.OfType)
+		}
+		return isSafeInputChange(o.OfType, n)
+	case schema.NamedType:
+		if n, ok := n.(schema.NamedType); ok {
+			return o.TypeName() == n.TypeName()
+		}
+		return false
+	}
+	return false
+}
+
+func isRequired(v *common.InputValue) bool {
+	_, nonNull := v.Type.(*common.NonNull)
+	return nonNull && v.Default == nil
+}
+
+func sortedNames(maps ...interface{}) []string {
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		switch m := m.(type) {
+		case map[string]schema.NamedType:
+			for name := range m {
+				seen[name] = true
+			}
+		case map[string]*schema.DirectiveDecl:
+			for name := range m {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func objectNames(objects []*schema.Object) []string {
+	names := make([]string, len(objects))
+	for i, o := range objects {
+		names[i] = o.Name
+	}
+	return names
+}
+
+func interfaceNames(intfs []*schema.Interface) []string {
+	names := make([]string, len(intfs))
+	for i, intf := range intfs {
+		names[i] = intf.Name
+	}
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package schemadiff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go/schemadiff"
+)
+
+const oldSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		user(id: ID!, withPosts: Boolean = false): User
+		users(first: Int): [User!]!
+		legacy: String
+	}
+
+	interface Node {
+		id: ID!
+	}
+
+	type User implements Node {
+		id: ID!
+		name: String
+		role: Role!
+	}
+
+	enum Role {
+		ADMIN
+		USER
+	}
+
+	input Filter {
+		role: Role
+		name: String!
+	}
+
+	union Result = User
+
+	directive @cache(maxAge: Int) on FIELD_DEFINITION | OBJECT
+
+	scalar Removed
+`
+
+const newSchema = `
+	schema {
+		query: Query
+	}
+
+	type Query {
+		user(id: ID, withPosts: Boolean = true, locale: String): User
+		users(first: Int!): [User!]!
+		search(filter: Filter): [Result!]!
+	}
+
+	interface Node {
+		id: ID!
+	}
+
+	type Group implements Node {
+		id: ID!
+	}
+
+	"""A registered user."""
+	type User implements Node {
+		id: ID!
+		name: String! @deprecated
+		role: Role
+	}
+
+	enum Role {
+		ADMIN
+		USER
+		GUEST
+	}
+
+	input Filter {
+		role: Role
+		name: String
+		limit: Int!
+	}
+
+	union Result = User | Group
+
+	directive @cache(maxAge: Int, scope: String!) repeatable on FIELD_DEFINITION
+`
+
+func TestCompare(t *testing.T) {
+	changes, err := schemadiff.Compare(oldSchema, newSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`BREAKING @cache: location OBJECT was removed from directive "@cache"`,
+		`SAFE @cache: directive "@cache" is now repeatable`,
+		`BREAKING @cache.scope: required argument "scope" was added to directive "@cache"`,
+		`BREAKING Filter.limit: required input field "Filter.limit" was added`,
+		`SAFE Filter.name: input field "Filter.name" changed type from "String!" to "String"`,
+		`SAFE Group: type "Group" was added`,
+		`BREAKING Query.legacy: field "Query.legacy" was removed`,
+		`SAFE Query.search: field "Query.search" was added`,
+		`SAFE Query.user.id: argument "Query.user.id" changed type from "ID!" to "ID"`,
+		`DANGEROUS Query.user.locale: optional argument "locale" was added to field "Query.user"`,
+		`DANGEROUS Query.user.withPosts: default value of argument "Query.user.withPosts" changed from false to true`,
+		`BREAKING Query.users.first: argument "Query.users.first" changed type from "Int" to "Int!"`,
+		`BREAKING Removed: type "Removed" was removed`,
+		`DANGEROUS Result: member "Group" was added to union "Result"`,
+		`DANGEROUS Role.GUEST: value "GUEST" was added to enum "Role"`,
+		`SAFE User: description of type "User" changed`,
+		`SAFE User.name: field "User.name" changed type from "String" to "String!"`,
+		`SAFE User.name: field "User.name" was deprecated`,
+		`BREAKING User.role: field "User.role" changed type from "Role!" to "Role"`,
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\n\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !schemadiff.HasBreaking(changes) {
+		t.Error("expected breaking changes")
+	}
+}
+
+func TestCompareSafe(t *testing.T) {
+	changes, err := schemadiff.Compare(oldSchema, oldSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("got changes %v for identical schemas", changes)
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	_, err := schemadiff.Compare(oldSchema, `type Query { a: Missing }`)
+	if err == nil || !strings.HasPrefix(err.Error(), "new schema: ") {
+		t.Errorf("got error %v, want an error about the new schema", err)
+	}
+}
@@ -0,0 +1,391 @@
+package graphql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const sdlSchema = `
+	"""
+	Marks an element with a tag.
+
+	  Tags are free-form.
+	"""
+	directive @tag(name: String!) repeatable on OBJECT | INTERFACE | FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM | SCALAR | UNION | INPUT_OBJECT | SCHEMA
+
+	schema @tag(name: "schema") {
+		query: Query
+	}
+
+	extend type Query @tag(name: "extended") {
+		"The current \"mood\"."
+		mood: Mood!
+	}
+
+	"""The root query type."""
+	type Query @tag(name: "a") @tag(name: "b") {
+		greet(
+			"""Who to greet."""
+			name: String! = "world" @tag(name: "arg")
+		): String!
+	}
+
+	extend type Query {
+		pet: Pet
+	}
+
+	interface Named {
+		name: String!
+	}
+
+	extend interface Named @tag(name: "interface")
+
+	type Dog implements & Named {
+		name: String!
+	}
+
+	type Cat implements Named {
+		name: String!
+	}
+
+	union Pet @tag(name: "union") = | Dog
+
+	extend union Pet = Cat
+
+	enum Mood @tag(name: "enum") {
+		HAPPY
+	}
+
+	extend enum Mood {
+		"""Not happy."""
+		SAD
+	}
+
+	input Filter @tag(name: "input") {
+		name: String @tag(name: "input field")
+	}
+
+	extend input Filter {
+		limit: Int
+	}
+
+	scalar Color
+
+	extend scalar Color @tag(name: "scalar")
+
+	extend schema @tag(name: "extended schema")
+`
+
+type sdlResolver struct{}
+
+func (r *sdlResolver) Greet(args struct{ Name string }) string {
+	return "Hello, " + args.Name + "!"
+}
+
+func (r *sdlResolver) Mood() string {
+	return "SAD"
+}
+
+func (r *sdlResolver) Pet() *sdlPetResolver {
+	return &sdlPetResolver{&sdlCatResolver{}}
+}
+
+type sdlPetResolver struct {
+	pet interface{}
+}
+
+func (r *sdlPetResolver) ToDog() (*sdlCatResolver, bool) {
+	return nil, false
+}
+
+func (r *sdlPetResolver) ToCat() (*sdlCatResolver, bool) {
+	c, ok := r.pet.(*sdlCatResolver)
+	return c, ok
+}
+
+type sdlCatResolver struct{}
+
+func (r *sdlCatResolver) Name() string {
+	return "Tom"
+}
+
+func TestSDL(t *testing.T) {
+	s := graphql.MustParseSchema(sdlSchema, &sdlResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				{
+					greet
+					mood
+					pet {
+						... on Cat {
+							name
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"greet": "Hello, world!",
+					"mood": "SAD",
+					"pet": {
+						"name": "Tom"
+					}
+				}
+			`,
+		},
+		{
+			Schema: s,
+			Query: `
+				{
+					query: __type(name: "Query") {
+						description
+						fields {
+							name
+							description
+							args {
+								description
+							}
+						}
+					}
+					mood: __type(name: "Mood") {
+						enumValues {
+							name
+							description
+						}
+					}
+					pet: __type(name: "Pet") {
+						possibleTypes {
+							name
+						}
+					}
+					filter: __type(name: "Filter") {
+						inputFields {
+							name
+						}
+					}
+					__schema {
+						directives {
+							name
+							description
+							isRepeatable
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"query": {
+						"description": "The root query type.",
+						"fields": [
+							{"name": "greet", "description": null, "args": [{"description": "Who to greet."}]},
+							{"name": "mood", "description": "The current \"mood\".", "args": []},
+							{"name": "pet", "description": null, "args": []}
+						]
+					},
+					"mood": {
+						"enumValues": [
+							{"name": "HAPPY", "description": null},
+							{"name": "SAD", "description": "Not happy."}
+						]
+					},
+					"pet": {
+						"possibleTypes": [{"name": "Dog"}, {"name": "Cat"}]
+					},
+					"filter": {
+						"inputFields": [{"name": "name"}, {"name": "limit"}]
+					},
+					"__schema": {
+						"directives": [
+							{"name": "defer", "description": "Directs the executor to deliver this fragment in a subsequent payload, if the request is\nexecuted incrementally.", "isRepeatable": false},
+							{"name": "deprecated", "description": "Marks an element of a GraphQL schema as no longer supported.", "isRepeatable": false},
+							{"name": "include", "description": "Directs the executor to include this field or fragment only when the ` + "`if`" + ` argument is true.", "isRepeatable": false},
+							{"name": "skip", "description": "Directs the executor to skip this field or fragment when the ` + "`if`" + ` argument is true.", "isRepeatable": false},
+							{"name": "specifiedBy", "description": "Exposes a URL that specifies the behavior of this scalar.", "isRepeatable": false},
+							{"name": "stream", "description": "Directs the executor to deliver the items of this list field in subsequent payloads, if the\nrequest is executed incrementally.", "isRepeatable": false},
+							{"name": "tag", "description": "Marks an element with a tag.\n\n  Tags are free-form.", "isRepeatable": true}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestSDLOperationValidation(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		directive @variable on VARIABLE_DEFINITION
+
+		schema {
+			query: Query
+		}
+
+		type Query {
+			greet(name: String! = "world"): String!
+		}
+	`, &sdlResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema:         s,
+			Query:          `{ greet }`,
+			ExpectedResult: `{"greet": "Hello, world!"}`,
+		},
+		{
+			Schema:         s,
+			Query:          `query($name: String! @variable) { greet(name: $name) }`,
+			Variables:      map[string]interface{}{"name": "you"},
+			ExpectedResult: `{"greet": "Hello, you!"}`,
+		},
+	})
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`query($name: String! @unknown) { greet(name: $name) }`, `Unknown directive "unknown".`},
+		{`query($name: String! @skip(if: true)) { greet(name: $name) }`, `Directive "skip" may not be used on VARIABLE_DEFINITION.`},
+	}
+	for _, tt := range tests {
+		result := s.Exec(context.Background(), tt.query, "", map[string]interface{}{"name": "you"})
+		if len(result.Errors) != 1 || result.Errors[0].Message != tt.want {
+			t.Errorf("%s: got errors %v, want %q", tt.query, result.Errors, tt.want)
+		}
+	}
+}
+
+func TestSDLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "extend undefined type",
+			schema: `type Query { a: Int } extend type Missing { b: Int }`,
+			want:   `cannot extend type "Missing" because it is not defined`,
+		},
+		{
+			name:   "extend with wrong kind",
+			schema: `type Query { a: Int } extend interface Query { b: Int }`,
+			want:   `cannot extend OBJECT "Query" with an extension of kind INTERFACE`,
+		},
+		{
+			name:   "duplicate field",
+			schema: `type Query { a: Int } extend type Query { a: Int }`,
+			want:   `field "a" of type "Query" is already defined`,
+		},
+		{
+			name:   "extend built-in type",
+			schema: `type Query { a: Int } extend scalar Int @deprecated`,
+			want:   `cannot extend built-in type "Int"`,
+		},
+		{
+			name:   "non-repeatable directive",
+			schema: `directive @d on OBJECT type Query @d @d { a: Int }`,
+			want:   `directive "d" is not repeatable`,
+		},
+		{
+			name:   "unterminated block string",
+			schema: `""" never closed type Query { a: Int }`,
+			want:   `unterminated block string`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseSchema("schema { query: Query } "+tt.schema, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSDL(t *testing.T) {
+	s := graphql.MustParseSchema(`
+		schema {
+			query: Query
+		}
+
+		"""Limits the cost of a field."""
+		directive @cost(value: Int = 1, reason: String) on FIELD_DEFINITION
+
+		type Query {
+			"""Finds a "thing"."""
+			find(
+				"The ID to look for."
+				id: ID!
+				kinds: [Kind!] = [A, B]
+			): Thing @cost(value: 3)
+			all(filter: Filter = {kind: A, name: "x\ty"}): [Thing!]! @cost
+			old: String @deprecated(reason: "Use find.")
+		}
+
+		enum Kind {
+			A
+			"""
+			Second kind.
+			Not the first.
+			"""
+			B @deprecated
+		}
+
+		input Filter {
+			kind: Kind
+			name: String = "any"
+		}
+
+		union Thing = Query
+	`, nil)
+
+	want := `schema {
+  query: Query
+}
+
+"""Limits the cost of a field."""
+directive @cost(value: Int = 1, reason: String) on FIELD_DEFINITION
+
+input Filter {
+  kind: Kind
+  name: String = "any"
+}
+
+enum Kind {
+  A
+  """
+  Second kind.
+  Not the first.
+  """
+  B @deprecated
+}
+
+type Query {
+  """Finds a "thing"."""
+  find(
+    """The ID to look for."""
+    id: ID!
+    kinds: [Kind!] = [A, B]
+  ): Thing @cost(value: 3)
+  all(filter: Filter = {kind: A, name: "x\ty"}): [Thing!]! @cost
+  old: String @deprecated(reason: "Use find.")
+}
+
+union Thing = Query
+`
+	if got := s.ToSDL(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	for _, schema := range []string{want, sdlSchema, interfacesSchema} {
+		sdl := graphql.MustParseSchema(schema, nil).ToSDL()
+		if got := graphql.MustParseSchema(sdl, nil).ToSDL(); got != sdl {
+			t.Errorf("printed schema does not survive a round trip:\n%s\nprinted again:\n%s", sdl, got)
+		}
+	}
+}
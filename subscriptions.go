@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/common"
+	"github.com/sevlyar/graphql-go/internal/exec"
+	"github.com/sevlyar/graphql-go/internal/query"
+	"github.com/sevlyar/graphql-go/introspection"
+	"github.com/sevlyar/graphql-go/trace"
+)
+
+// Subscribe executes a subscription operation. The resolver of the subscription's root field has to
+// return a receive channel; every value sent on it is resolved with the query's selection set and
+// delivered as a separate Response. The returned channel is closed when the resolver closes its
+// channel or when the context gets cancelled.
+//
+// Queries and mutations are accepted as well, they are executed like with ExecIncremental.
+// Errors in the query itself (syntax, validation, unknown operation) are reported the same way. An
+// error is only returned if the schema was created without a resolver.
+func (s *Schema) Subscribe(ctx context.Context, queryString string, operationName string, variables map[string]interface{}) (<-chan *Response, error) {
+	if s.res == nil {
+		return nil, fmt.Errorf("schema created without resolver, can not subscribe")
+	}
+
+	doc, errs := s.parseAndValidate(queryString)
+	if len(errs) != 0 {
+		return sendAndReturnClosed(&Response{Errors: errs}), nil
+	}
+
+	op, err := getOperation(doc, operationName)
+	if err != nil {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{errors.Errorf("%s", err)}}), nil
+	}
+
+	if op.Type != query.Subscription {
+		return s.ExecIncremental(ctx, queryString, operationName, variables), nil
+	}
+
+	extensions, qErr := s.checkComplexity(doc, op, variables)
+	if qErr != nil {
+		return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{qErr}, Extensions: extensions}), nil
+	}
+
+	ctx, ext := withExtensions(ctx, extensions)
+
+	r := s.newRequest(doc, variables)
+	varTypes := make(map[string]*introspection.Type)
+	for _, v := range op.Vars {
+		t, err := common.ResolveType(v.Type, s.schema.Resolve)
+		if err != nil {
+			return sendAndReturnClosed(&Response{Errors: []*errors.QueryError{err}}), nil
+		}
+		varTypes[v.Name.Name] = introspection.WrapType(t)
+	}
+	traceCtx, finish := s.tracer.TraceQuery(ctx, queryString, operationName, variables, varTypes)
+	return forwardResponses(ctx, r.Subscribe(traceCtx, s.res, op), finish, ext), nil
+}
+
+// forwardResponses converts the responses of the executor and finishes the trace once the
+// executor is done. Every Response gets the extension entries collected since the previous one.
+func forwardResponses(ctx context.Context, responses <-chan *exec.Response, finish trace.TraceQueryFinishFunc, ext *responseExtensions) <-chan *Response {
+	c := make(chan *Response)
+	go func() {
+		defer close(c)
+		var errs []*errors.QueryError
+		for resp := range responses {
+			errs = append(errs, resp.Errors...)
+			select {
+			case c <- &Response{Data: resp.Data, Items: resp.Items, Path: resp.Path, Label: resp.Label, Errors: resp.Errors, Extensions: ext.take(), HasNext: resp.HasNext}:
+			case <-ctx.Done():
+				// keep draining, the executor stops as soon as it notices the cancellation
+			}
+		}
+		finish(errs)
+	}()
+	return c
+}
+
+func sendAndReturnClosed(resp *Response) <-chan *Response {
+	c := make(chan *Response, 1)
+	c <- resp
+	close(c)
+	return c
+}
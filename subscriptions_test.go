@@ -0,0 +1,168 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+)
+
+const counterSchema = `
+	schema {
+		query: Query
+		subscription: Subscription
+	}
+
+	type Query {
+		hello: String!
+	}
+
+	type Subscription {
+		counter(upTo: Int!): Tick!
+	}
+
+	type Tick {
+		n: Int!
+		label: String!
+	}
+`
+
+type counterResolver struct {
+	forever bool
+}
+
+func (r *counterResolver) Hello() string {
+	return "Hello world!"
+}
+
+func (r *counterResolver) Counter(ctx context.Context, args struct{ UpTo int32 }) <-chan *tickResolver {
+	c := make(chan *tickResolver)
+	go func() {
+		defer close(c)
+		for i := int32(1); r.forever || i <= args.UpTo; i++ {
+			select {
+			case c <- &tickResolver{n: i}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c
+}
+
+type tickResolver struct {
+	n int32
+}
+
+func (r *tickResolver) N() int32 {
+	return r.n
+}
+
+func (r *tickResolver) Label() string {
+	return "tick"
+}
+
+func TestSubscribe(t *testing.T) {
+	s := graphql.MustParseSchema(counterSchema, &counterResolver{})
+
+	c, err := s.Subscribe(context.Background(), `subscription { counter(upTo: 3) { n label } }`, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for resp := range c {
+		if len(resp.Errors) != 0 {
+			t.Fatal(resp.Errors[0])
+		}
+		got = append(got, string(resp.Data))
+	}
+
+	want := []string{
+		`{"counter":{"n":1,"label":"tick"}}`,
+		`{"counter":{"n":2,"label":"tick"}}`,
+		`{"counter":{"n":3,"label":"tick"}}`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d responses, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("response %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	s := graphql.MustParseSchema(counterSchema, &counterResolver{forever: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := s.Subscribe(ctx, `subscription { counter(upTo: 0) { n } }`, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-c
+	cancel()
+	for range c {
+		// the channel has to be closed after cancellation
+	}
+}
+
+func TestSubscribeQuery(t *testing.T) {
+	s := graphql.MustParseSchema(counterSchema, &counterResolver{})
+
+	c, err := s.Subscribe(context.Background(), `{ hello }`, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []*graphql.Response
+	for resp := range c {
+		responses = append(responses, resp)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("got %d responses, want 1", len(responses))
+	}
+	data, _ := json.Marshal(responses[0])
+	if string(data) != `{"data":{"hello":"Hello world!"}}` {
+		t.Errorf("unexpected response %s", data)
+	}
+}
+
+func TestSubscribeInvalidQuery(t *testing.T) {
+	s := graphql.MustParseSchema(counterSchema, &counterResolver{})
+
+	c, err := s.Subscribe(context.Background(), `subscription { counter { unknown } }`, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := <-c
+	if len(resp.Errors) == 0 {
+		t.Error("errors expected")
+	}
+	if _, ok := <-c; ok {
+		t.Error("channel should be closed after the error response")
+	}
+}
+
+func TestExecSubscription(t *testing.T) {
+	s := graphql.MustParseSchema(counterSchema, &counterResolver{})
+	query := `subscription { counter(upTo: 1) { n } }`
+	want := `resolver of subscription field "counter" returns a channel, use Subscribe to execute the operation`
+
+	result := s.Exec(context.Background(), query, "", nil)
+	if result.Data != nil || len(result.Errors) != 1 || result.Errors[0].Message != want {
+		t.Errorf("unexpected result %v", result)
+	}
+
+	var responses []*graphql.Response
+	for resp := range s.ExecIncremental(context.Background(), query, "", nil) {
+		responses = append(responses, resp)
+	}
+	if len(responses) != 1 || len(responses[0].Errors) != 1 || responses[0].Errors[0].Message != want {
+		t.Errorf("unexpected responses %v", responses)
+	}
+}
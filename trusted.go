@@ -0,0 +1,123 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sevlyar/graphql-go/errors"
+	"github.com/sevlyar/graphql-go/internal/query"
+)
+
+// TrustedDocuments restricts the schema to the documents of the given manifest, a JSON object
+// mapping document IDs to query strings. Any other query string is rejected before it is parsed.
+// IDs of the form "sha256:<hex>" or plain 64 digit hex strings have to match the SHA-256 hash of
+// their document.
+//
+// The manifest is checked by ParseSchema: it fails if a document can not be parsed, does not
+// validate against the schema or does not match its hash.
+func TrustedDocuments(manifest []byte) SchemaOpt {
+	return func(s *Schema) {
+		s.trustedManifest = manifest
+	}
+}
+
+type trustedDocuments struct {
+	byID   map[string]string
+	byText map[string]*query.Document
+}
+
+// loadTrustedDocuments parses and validates all documents of the manifest and reports every
+// broken document.
+func (s *Schema) loadTrustedDocuments() error {
+	var manifest map[string]string
+	if err := json.Unmarshal(s.trustedManifest, &manifest); err != nil {
+		return fmt.Errorf("invalid trusted documents manifest: %s", err)
+	}
+
+	ids := make([]string, 0, len(manifest))
+	for id := range manifest {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	td := &trustedDocuments{
+		byID:   make(map[string]string, len(manifest)),
+		byText: make(map[string]*query.Document, len(manifest)),
+	}
+	var problems []string
+	for _, id := range ids {
+		text := manifest[id]
+		hash := trustedDocumentHash(id)
+		if hash != "" {
+			sum := sha256.Sum256([]byte(text))
+			if hex.EncodeToString(sum[:]) != hash {
+				problems = append(problems, fmt.Sprintf("document %q does not match its hash", id))
+				continue
+			}
+		}
+
+		doc, errs := s.parseAndValidateUncached(text)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("document %q: %s", id, err))
+		}
+		if len(errs) != 0 {
+			continue
+		}
+
+		td.byID[id] = text
+		if hash != "" {
+			td.byID[hash] = text
+		}
+		td.byText[text] = doc
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("invalid trusted documents:\n%s", strings.Join(problems, "\n"))
+	}
+	s.trusted = td
+	return nil
+}
+
+// trustedDocumentHash returns the lower case hex hash contained in the document ID, or "" if the ID
+// is no hash.
+func trustedDocumentHash(id string) string {
+	hash := strings.ToLower(strings.TrimPrefix(id, "sha256:"))
+	if len(hash) != 2*sha256.Size {
+		return ""
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return ""
+	}
+	return hash
+}
+
+// TrustedDocument returns the query string of the trusted document with the given ID. Hash based
+// IDs are found with and without "sha256:" prefix. It always fails if the schema was created
+// without the TrustedDocuments option.
+func (s *Schema) TrustedDocument(id string) (string, bool) {
+	if s.trusted == nil {
+		return "", false
+	}
+	if text, ok := s.trusted.byID[id]; ok {
+		return text, true
+	}
+	if hash := trustedDocumentHash(id); hash != "" {
+		text, ok := s.trusted.byID[hash]
+		return text, ok
+	}
+	return "", false
+}
+
+func (s *Schema) trustedDocument(queryString string) (*query.Document, []*errors.QueryError) {
+	doc, ok := s.trusted.byText[queryString]
+	if !ok {
+		err := errors.Errorf("query is not a trusted document")
+		err.Extensions = map[string]interface{}{"code": "UNTRUSTED_DOCUMENT"}
+		return nil, []*errors.QueryError{err}
+	}
+	return doc, nil
+}
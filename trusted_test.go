@@ -0,0 +1,87 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/example/starwars"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestTrustedDocuments(t *testing.T) {
+	heroQuery := `{ hero { name } }`
+	manifest, _ := json.Marshal(map[string]string{
+		"sha256:" + sha256Hex(heroQuery): heroQuery,
+		"droid":                          `{ droid(id: "2000") { name } }`,
+	})
+	s := graphql.MustParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.TrustedDocuments(manifest))
+
+	result := s.Exec(context.Background(), heroQuery, "", nil)
+	if len(result.Errors) != 0 || string(result.Data) != `{"hero":{"name":"R2-D2"}}` {
+		t.Errorf("unexpected result %s %v", result.Data, result.Errors)
+	}
+
+	result = s.Exec(context.Background(), `{ hero { id } }`, "", nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "query is not a trusted document" {
+		t.Errorf("expected untrusted document to be rejected, got %s %v", result.Data, result.Errors)
+	}
+
+	for _, id := range []string{sha256Hex(heroQuery), "sha256:" + sha256Hex(heroQuery)} {
+		if q, ok := s.TrustedDocument(id); !ok || q != heroQuery {
+			t.Errorf("TrustedDocument(%q) = %q, %v", id, q, ok)
+		}
+	}
+	if q, ok := s.TrustedDocument("droid"); !ok || q != `{ droid(id: "2000") { name } }` {
+		t.Errorf("TrustedDocument(\"droid\") = %q, %v", q, ok)
+	}
+	if _, ok := s.TrustedDocument("unknown"); ok {
+		t.Error("unknown document found")
+	}
+}
+
+func TestTrustedDocumentsInvalidManifest(t *testing.T) {
+	manifest, _ := json.Marshal(map[string]string{
+		"a":                       `{ unknown }`,
+		"b":                       `{ hero { name }`,
+		"sha256:" + sha256Hex(""): `{ hero { name } }`,
+		"d":                       `{ hero { name } }`,
+	})
+	_, err := graphql.ParseSchema(starwars.Schema, &starwars.Resolver{}, graphql.TrustedDocuments(manifest))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{`document "a"`, `document "b"`, `does not match its hash`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), `document "d"`) {
+		t.Errorf("error %q mentions valid document", err)
+	}
+}
+
+func TestTrustedDocumentsToJSON(t *testing.T) {
+	want, err := graphql.MustParseSchema(starwars.Schema, nil).ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, _ := json.Marshal(map[string]string{"hero": `{ hero { name } }`})
+	s := graphql.MustParseSchema(starwars.Schema, nil, graphql.TrustedDocuments(manifest), graphql.MaxDepth(3), graphql.MaxComplexity(10))
+	got, err := s.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Error("ToJSON is affected by trusted documents or limits")
+	}
+}
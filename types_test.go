@@ -0,0 +1,197 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/gqltesting"
+)
+
+const typesSchema = `
+	schema {
+		query: Query
+	}
+
+	enum Color {
+		RED
+		GREEN
+		BLUE
+	}
+
+	enum Size {
+		SMALL
+		LARGE
+	}
+
+	type Query {
+		sum(a: Int!, b: Int!): Int!
+		repeat(s: String!, times: Int!): String!
+		half(f: Float!): Float!
+		big: Int!
+		mix(colors: [Color!]!, size: Size = LARGE): String!
+		next(color: Color!): Color!
+		invalidColor: Color
+	}
+`
+
+type color int
+
+var colorNames = []string{"RED", "GREEN", "BLUE"}
+
+func (c color) String() string {
+	if c < 0 || int(c) >= len(colorNames) {
+		return fmt.Sprintf("color(%d)", int(c))
+	}
+	return colorNames[c]
+}
+
+func (c *color) UnmarshalText(text []byte) error {
+	for i, name := range colorNames {
+		if name == string(text) {
+			*c = color(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %q", text)
+}
+
+type size string
+
+type typesResolver struct{}
+
+func (r *typesResolver) Sum(args struct{ A, B int }) int64 {
+	return int64(args.A) + int64(args.B)
+}
+
+func (r *typesResolver) Repeat(args struct {
+	S     string
+	Times uint
+}) string {
+	return strings.Repeat(args.S, int(args.Times))
+}
+
+func (r *typesResolver) Half(args struct{ F float32 }) float32 {
+	return args.F / 2
+}
+
+func (r *typesResolver) Big() int64 {
+	return 1 << 40
+}
+
+func (r *typesResolver) Mix(args struct {
+	Colors []color
+	Size   size
+}) string {
+	return fmt.Sprintf("%v %s", args.Colors, args.Size)
+}
+
+func (r *typesResolver) Next(args struct{ Color color }) color {
+	return (args.Color + 1) % 3
+}
+
+func (r *typesResolver) InvalidColor() *color {
+	c := color(7)
+	return &c
+}
+
+func TestNativeTypes(t *testing.T) {
+	s := graphql.MustParseSchema(typesSchema, &typesResolver{})
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: s,
+			Query: `
+				query($b: Int!, $colors: [Color!]!) {
+					sum(a: 2147483647, b: $b)
+					repeat(s: "ab", times: 3)
+					half(f: 1.5)
+					literal: mix(colors: [RED, BLUE], size: SMALL)
+					variable: mix(colors: $colors)
+					next(color: BLUE)
+				}
+			`,
+			Variables: map[string]interface{}{
+				"b":      -2147483647.0,
+				"colors": []interface{}{"GREEN"},
+			},
+			ExpectedResult: `
+				{
+					"sum": 0,
+					"repeat": "ababab",
+					"half": 0.75,
+					"literal": "[RED BLUE] SMALL",
+					"variable": "[GREEN] LARGE",
+					"next": "RED"
+				}
+			`,
+		},
+	})
+}
+
+func TestNativeTypesErrors(t *testing.T) {
+	s := graphql.MustParseSchema(typesSchema, &typesResolver{})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{"negative uint", `query($n: Int!) { repeat(s: "a", times: $n) }`, map[string]interface{}{"n": -1.0}, "-1 overflows uint"},
+		{"Int out of range", `query($b: Int!) { sum(a: 1, b: $b) }`, map[string]interface{}{"b": 1 << 40}, "not a 32-bit integer"},
+		{"result out of range", `{ big }`, nil, "could not serialize Int: 1099511627776 is not a 32-bit integer"},
+		{"invalid enum result", `{ invalidColor }`, nil, "invalid value color(7) for enum Color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := s.Exec(context.Background(), tt.query, "", tt.variables)
+			if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, tt.want) {
+				t.Errorf("got errors %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
+
+type unnamedColorResolver struct{ typesResolver }
+
+func (r *unnamedColorResolver) Next(args struct{ Color int }) color {
+	return 0
+}
+
+type shortColor int
+
+func (c shortColor) String() string {
+	return colorNames[c][:1]
+}
+
+func (c *shortColor) UnmarshalText(text []byte) error {
+	return (*color)(c).UnmarshalText(text)
+}
+
+type shortColorResolver struct{ typesResolver }
+
+func (r *shortColorResolver) InvalidColor() *shortColor {
+	return nil
+}
+
+func TestNativeTypesSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver interface{}
+		want     string
+	}{
+		{"plain int enum", &unnamedColorResolver{}, "can not use int as enum Color, expected a string type or an integer type implementing encoding.TextUnmarshaler and fmt.Stringer"},
+		{"inconsistent enum", &shortColorResolver{}, `graphql_test.shortColor unmarshals value "RED" of enum Color into "R"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.ParseSchema(typesSchema, tt.resolver)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package graphql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sevlyar/graphql-go"
+	"github.com/sevlyar/graphql-go/errors"
+)
+
+func TestSchemaValidation(t *testing.T) {
+	_, err := graphql.ParseSchema(`
+		schema {
+			query: Query
+		}
+
+		type Query {
+			a: Int
+			a: String
+			b(x: Int = "one"): Filter
+		}
+
+		type Query {
+			c: Int
+		}
+
+		interface Named {
+			name(upper: Boolean): String!
+		}
+
+		type Person implements Named {
+			name: String
+		}
+
+		enum Color {
+			RED
+			RED
+			null
+		}
+
+		input Filter {
+			color: Color = BLUE
+			next: Filter!
+		}
+
+		union Result = Person | Named
+
+		directive @d(n: Int!) on FIELD_DEFINITION | NOWHERE
+
+		type Thing @d {
+			__name: String @d
+		}
+
+		schema {
+			query: Thing
+		}
+	`, nil)
+
+	errs, ok := err.(errors.SchemaErrors)
+	if !ok {
+		t.Fatalf("got error %#v, want errors.SchemaErrors", err)
+	}
+	want := []string{
+		`field "a" of type "Query" is defined more than once (line 8, column 4)`,
+		`field "b" of type "Query" has the input type "Filter", which can not be used as output (line 9, column 4)`,
+		`invalid default value "one" for argument "x" of field "Query.b": Expected type "Int", found "one". (line 9, column 15)`,
+		`type "Query" is defined more than once (line 12, column 8)`,
+		`field "name" of type "Person" has type "String" which is not a subtype of "String!" required by "Named" (line 21, column 4)`,
+		`field "name" of type "Person" must have argument "upper" of type "Boolean" required by "Named" (line 21, column 4)`,
+		`value "RED" of enum "Color" is defined more than once (line 26, column 4)`,
+		`enum "Color" can not have the value "null" (line 27, column 4)`,
+		`input type "Filter" references itself through non-null fields: Filter -> Filter (line 30, column 9)`,
+		`invalid default value BLUE for field "color" of input type "Filter": Expected type "Color", found BLUE. (line 31, column 19)`,
+		`type "Named" is not an object (line 35, column 27)`,
+		`directive "d" has the unknown location "NOWHERE" (line 37, column 13)`,
+		`directive "d" may not be used on OBJECT (line 39, column 14)`,
+		`directive "d" is missing the required argument "n" (line 39, column 14)`,
+		`name "__name" must not begin with "__", which is reserved by GraphQL introspection (line 40, column 4)`,
+		`directive "d" is missing the required argument "n" (line 40, column 19)`,
+		`schema is defined more than once (line 43, column 3)`,
+		`query operation is already defined with the type "Query", conflicting with "Thing" (line 44, column 11)`,
+	}
+	got := make([]string, len(errs))
+	for i, err := range errs {
+		got[i] = fmt.Sprintf("%s (line %d, column %d)", err.Message, err.Locations[0].Line, err.Locations[0].Column)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%s", len(got), len(want), err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("error %d:\ngot  %s\nwant %s", i, got[i], want[i])
+		}
+	}
+}